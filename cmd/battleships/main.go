@@ -7,15 +7,14 @@ import (
 	"os"
 	"strings"
 
-	"bytes"
-
 	"github.com/jkosecki/battleships"
 )
 
 const (
-	inputMessage     = "Please insert a new position in form '[A-J][1-10]': "
-	readErrorMessage = "Problems while reading input occured. Please try again"
-	errorMessage     = "Your input '%v' doesn't match the required form. Please type again: "
+	inputMessage       = "Please insert a new position in form '[A-J][1-10]': "
+	readErrorMessage   = "Problems while reading input occured. Please try again"
+	errorMessage       = "Your input '%v' doesn't match the required form. Please type again: "
+	alreadyShotMessage = "You've already shot at this position. Please choose another one"
 )
 
 func main() {
@@ -23,16 +22,24 @@ func main() {
 	p := newPlayer(os.Stdin, os.Stdout)
 	g := &battleships.Game{}
 
-	g.FillBoard([]battleships.Ship{
+	err := g.FillBoard([]battleships.Ship{
 		battleships.NewShip(5),
 		battleships.NewShip(4),
 		battleships.NewShip(4),
 	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Couldn't set up the game: %v\n", err)
+		os.Exit(1)
+	}
 
 	for g.Playable() {
-		printBoard(g.Board(true))
+		fmt.Print(g.Board(true))
 		pos := p.GetShotPosition()
 		hit, sunk, err := g.Shot(pos)
+		if err == battleships.ErrAlreadyShot {
+			fmt.Println(alreadyShotMessage)
+			continue
+		}
 		if err != nil {
 			fmt.Println(err)
 			return
@@ -46,7 +53,7 @@ func main() {
 		}
 		fmt.Println()
 	}
-	printBoard(g.Board(false))
+	fmt.Print(g.Board(false))
 	fmt.Printf("Game over. All ships are sunk after %v shots\n", g.Stats.ShotsFired)
 }
 
@@ -78,21 +85,3 @@ func (p *consolePlayer) GetShotPosition() battleships.Position {
 		}
 	}
 }
-
-func printBoard(board *battleships.Board) {
-	buf := bytes.Buffer{}
-	buf.WriteString("  ")
-	for i := 0; i < battleships.Cols; i++ {
-		buf.WriteString(fmt.Sprintf("%3d", i+1))
-	}
-	buf.WriteString("\n")
-
-	for i := 0; i < battleships.Rows; i++ {
-		buf.WriteString(fmt.Sprintf("%2c", 'A'+i))
-		for j := 0; j < battleships.Cols; j++ {
-			buf.WriteString(fmt.Sprintf("%3c", board[i][j]))
-		}
-		buf.WriteString("\n")
-	}
-	fmt.Print(buf.String())
-}
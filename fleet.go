@@ -0,0 +1,257 @@
+package battleships
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// StandardFleet returns ships used in the classic game: a carrier, a battleship, two cruisers and a destroyer
+func StandardFleet() []Ship {
+	return []Ship{NewShip(5), NewShip(4), NewShip(3), NewShip(3), NewShip(2)}
+}
+
+// ShipStatus describes current state of a single ship placed on the board
+type ShipStatus struct {
+	Size   uint8
+	Health uint8
+	// Orientation is NoDirection for single slot ships
+	Orientation Direction
+}
+
+// FleetStatus returns status of every ship placed on the board in order of placement
+func (g *Game) FleetStatus() []ShipStatus {
+	var fleet []ShipStatus
+	for _, s := range g.ships {
+		fleet = append(fleet, s.status())
+	}
+	return fleet
+}
+
+// ShipIndexAt returns index of the ship placed at given position in order of placement, the same as in FleetStatus.
+// If ships overlap, the first of them is returned. Returns false, if there is no ship at the position
+func (g *Game) ShipIndexAt(pos Position) (int, bool) {
+	if !isWithinBoard(pos.row, pos.col) || !isShipSlot(g.board.At(pos)) {
+		return 0, false
+	}
+	if ships := g.shipsAt(pos); len(ships) > 0 {
+		return ships[0], true
+	}
+	return 0, false
+}
+
+// SunkShipsList returns status of every ship, that has already sunk, in order of placement
+func (g *Game) SunkShipsList() []ShipStatus {
+	var sunk []ShipStatus
+	for _, s := range g.ships {
+		if s.health == 0 {
+			sunk = append(sunk, s.status())
+		}
+	}
+	return sunk
+}
+
+// RemainingShipSizes returns sizes of all ships, that are still alive, in order of placement
+func (g *Game) RemainingShipSizes() []uint8 {
+	var sizes []uint8
+	for _, s := range g.ships {
+		if s.health > 0 {
+			sizes = append(sizes, s.size)
+		}
+	}
+	return sizes
+}
+
+// SmallestRemainingShip returns size of the smallest ship, that isn't sunk yet. Returns false, if all ships are sunk
+func (g *Game) SmallestRemainingShip() (uint8, bool) {
+	sizes := g.RemainingShipSizes()
+	if len(sizes) == 0 {
+		return 0, false
+	}
+	smallest := sizes[0]
+	for _, size := range sizes {
+		if size < smallest {
+			smallest = size
+		}
+	}
+	return smallest, true
+}
+
+// FleetHealthPercent returns total remaining health of all ships as a percentage of their total initial health.
+// Returns 0, if there are no ships on the board
+func (g *Game) FleetHealthPercent() float64 {
+	var health, size int
+	for _, s := range g.ships {
+		health += int(s.health)
+		size += int(s.size)
+	}
+	if size == 0 {
+		return 0
+	}
+	return 100 * float64(health) / float64(size)
+}
+
+// FleetBreakdown returns number of ships still afloat and already sunk for every ship size present in the fleet
+func (g *Game) FleetBreakdown() map[uint8]struct{ Afloat, Sunk int } {
+	breakdown := make(map[uint8]struct{ Afloat, Sunk int })
+	for _, s := range g.FleetStatus() {
+		counts := breakdown[s.Size]
+		if s.Health == 0 {
+			counts.Sunk++
+		} else {
+			counts.Afloat++
+		}
+		breakdown[s.Size] = counts
+	}
+	return breakdown
+}
+
+// FleetArea returns total size of all ships placed on the board
+func (g *Game) FleetArea() int {
+	area := 0
+	for _, s := range g.ships {
+		area += int(s.size)
+	}
+	return area
+}
+
+// PerfectGameShots returns minimum number of shots needed to sink all of the ships knowing their layout.
+// It equals FleetArea, unless ships overlap, in which case every shared slot is counted once,
+// or are armored, in which case every armored slot needs as many additional shots as hits it still withstands
+func (g *Game) PerfectGameShots() int {
+	shots := 0
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			if isShipSlot(g.board[i][j]) {
+				shots += 1 + int(g.armor[i][j])
+			}
+		}
+	}
+	return shots
+}
+
+// LayoutNotation describes placement of every ship in order of placement by its name and occupied slots,
+// e.g. "Carrier A1-A5". Single slot ships are described by a single position, e.g. "Patrol boat J10",
+// and shaped ships by all of their slots, e.g. "Cruiser A1,B1,B2"
+func (g *Game) LayoutNotation() []string {
+	var notation []string
+	for _, p := range g.ExportLayout() {
+		if len(p.Ship.shape) > 0 {
+			cells := make([]string, len(p.Ship.shape))
+			for i, o := range p.Ship.shape {
+				cells[i] = Position{row: p.Position.row + o.row, col: p.Position.col + o.col}.String()
+			}
+			notation = append(notation, fmt.Sprintf("%v %v", shipName(p.Ship.size), strings.Join(cells, ",")))
+			continue
+		}
+		end := p.Position
+		if p.Direction == Horizontal {
+			end.col = g.wrapCol(end.col + p.Ship.size - 1)
+		} else {
+			end.row = g.wrapRow(end.row + p.Ship.size - 1)
+		}
+		if end == p.Position {
+			notation = append(notation, fmt.Sprintf("%v %v", shipName(p.Ship.size), p.Position))
+		} else {
+			notation = append(notation, fmt.Sprintf("%v %v-%v", shipName(p.Ship.size), p.Position, end))
+		}
+	}
+	return notation
+}
+
+// ClusterScore returns average distance between centres of all pairs of ships, which describes how spread out the layout is.
+// Higher scores mean ships are further apart. Returns 0, if there are less than two ships on the board
+func (g *Game) ClusterScore() float64 {
+	type point struct{ row, col float64 }
+	centroids := make([]point, len(g.ships))
+	for i, s := range g.ships {
+		for _, c := range s.cells {
+			centroids[i].row += float64(c.row) / float64(len(s.cells))
+			centroids[i].col += float64(c.col) / float64(len(s.cells))
+		}
+	}
+
+	total, pairs := 0.0, 0
+	for i := range centroids {
+		for j := i + 1; j < len(centroids); j++ {
+			total += math.Hypot(centroids[i].row-centroids[j].row, centroids[i].col-centroids[j].col)
+			pairs++
+		}
+	}
+	if pairs == 0 {
+		return 0
+	}
+	return total / float64(pairs)
+}
+
+// LayoutQualityOK returns false, if ships are clustered so much, that ClusterScore is lower than MinClusterScore
+func (g *Game) LayoutQualityOK() bool {
+	return len(g.ships) < 2 || g.ClusterScore() >= MinClusterScore
+}
+
+// shipName returns name of the ship's class used in the classic game, which is determined by the ship's size
+func shipName(size uint8) string {
+	switch size {
+	case 5:
+		return "Carrier"
+	case 4:
+		return "Battleship"
+	case 3:
+		return "Cruiser"
+	case 2:
+		return "Destroyer"
+	case 1:
+		return "Patrol boat"
+	}
+	return fmt.Sprintf("Ship of size %v", size)
+}
+
+func (s Ship) status() ShipStatus {
+	return ShipStatus{Size: s.size, Health: s.health, Orientation: s.direction}
+}
+
+// OccupancyRatio returns total size of the ships divided by area of a board with given dimensions.
+// Crowded boards make random placement more likely to fail. Returns 0 for boards without any slots
+func OccupancyRatio(ships []Ship, rows, cols int) float64 {
+	if rows <= 0 || cols <= 0 {
+		return 0
+	}
+	area := 0
+	for _, s := range ships {
+		area += int(s.size)
+	}
+	return float64(area) / float64(rows*cols)
+}
+
+// ValidateFleet checks, if given ships match exactly the spec describing required number of ships of each size.
+// Returns error describing all of the mismatches found
+func ValidateFleet(ships []Ship, spec map[uint8]int) error {
+	counts := make(map[uint8]int)
+	for _, s := range ships {
+		counts[s.size]++
+	}
+
+	var sizes []int
+	for size := range counts {
+		sizes = append(sizes, int(size))
+	}
+	for size := range spec {
+		if _, ok := counts[size]; !ok {
+			sizes = append(sizes, int(size))
+		}
+	}
+	sort.Ints(sizes)
+
+	var mismatches []string
+	for _, size := range sizes {
+		expected, got := spec[uint8(size)], counts[uint8(size)]
+		if expected != got {
+			mismatches = append(mismatches, fmt.Sprintf("expected %v ships of size %v, got %v", expected, size, got))
+		}
+	}
+	if len(mismatches) > 0 {
+		return fmt.Errorf("Invalid fleet: %v", strings.Join(mismatches, ", "))
+	}
+	return nil
+}
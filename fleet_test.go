@@ -0,0 +1,324 @@
+package battleships
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestValidateFleet(t *testing.T) {
+	spec := map[uint8]int{5: 1, 4: 2}
+	data := []struct {
+		ships []Ship
+		valid bool
+	}{
+		{[]Ship{NewShip(5), NewShip(4), NewShip(4)}, true},
+		{[]Ship{NewShip(4), NewShip(5), NewShip(4)}, true},
+		{[]Ship{NewShip(5), NewShip(4)}, false},
+		{[]Ship{NewShip(5), NewShip(4), NewShip(4), NewShip(4)}, false},
+		{[]Ship{NewShip(5), NewShip(4), NewShip(4), NewShip(2)}, false},
+		{nil, false},
+	}
+
+	for _, d := range data {
+		err := ValidateFleet(d.ships, spec)
+
+		if (err == nil) != d.valid {
+			t.Errorf("Expected valid: %v, got error: %v for ships: %v", d.valid, err, d.ships)
+		}
+	}
+}
+
+func TestFleetStatus_orientationReported(t *testing.T) {
+	g := newTestGame(
+		testShip{4, Position{0, 0}, Horizontal},
+		testShip{3, Position{2, 0}, Vertical},
+		testShip{1, Position{9, 9}, Vertical},
+	)
+	g.Shot(Position{3, 0})
+
+	expected := []ShipStatus{
+		{Size: 4, Health: 4, Orientation: Horizontal},
+		{Size: 3, Health: 2, Orientation: Vertical},
+		{Size: 1, Health: 1, Orientation: NoDirection},
+	}
+	fleet := g.FleetStatus()
+	if len(fleet) != len(expected) {
+		t.Fatalf("Expected number of ships: %v, got: %v", len(expected), len(fleet))
+	}
+	for i, e := range expected {
+		if fleet[i] != e {
+			t.Errorf("Expected status: %v, got: %v", e, fleet[i])
+		}
+	}
+}
+
+func TestFleetStatus_eachShipListedOnce(t *testing.T) {
+	g := Game{}
+	ships := []Ship{NewShip(5), NewShip(4), NewShip(4), NewShip(1)}
+	g.FillBoard(ships)
+
+	if len(g.FleetStatus()) != g.PlacedShipCount() {
+		t.Errorf("Expected number of ships: %v, got: %v", g.PlacedShipCount(), len(g.FleetStatus()))
+	}
+	if len(g.FleetStatus()) != len(ships) {
+		t.Errorf("Expected number of ships: %v, got: %v", len(ships), len(g.FleetStatus()))
+	}
+}
+
+func TestSunkShipsListAndRemainingShipSizes(t *testing.T) {
+	g := newTestGame(
+		testShip{2, Position{0, 0}, Horizontal},
+		testShip{3, Position{2, 0}, Vertical},
+		testShip{4, Position{9, 0}, Horizontal},
+	)
+	for _, p := range []Position{{0, 0}, {0, 1}, {2, 0}, {9, 0}} {
+		g.Shot(p)
+	}
+
+	sunk := g.SunkShipsList()
+	if len(sunk) != 1 || sunk[0].Size != 2 {
+		t.Errorf("Expected a single sunk ship of size 2, got: %v", sunk)
+	}
+	sizes := g.RemainingShipSizes()
+	if len(sizes) != 2 || sizes[0] != 3 || sizes[1] != 4 {
+		t.Errorf("Expected remaining sizes: %v, got: %v", []uint8{3, 4}, sizes)
+	}
+}
+
+func TestShipIndexAt_cellsOfOneShip(t *testing.T) {
+	g := newTestGame(testShip{3, Position{0, 0}, Horizontal}, testShip{2, Position{5, 5}, Vertical})
+	g.Shot(Position{0, 1})
+
+	first, ok := g.ShipIndexAt(Position{0, 0})
+	if !ok {
+		t.Fatal("Expected a ship at A1")
+	}
+	for _, p := range []Position{{0, 1}, {0, 2}} {
+		if idx, ok := g.ShipIndexAt(p); !ok || idx != first {
+			t.Errorf("Expected ship index at %v: %v, got: %v, %v", p, first, idx, ok)
+		}
+	}
+	for _, p := range []Position{{5, 5}, {6, 5}} {
+		if idx, ok := g.ShipIndexAt(p); !ok || idx == first {
+			t.Errorf("Expected ship index at %v different from: %v, got: %v, %v", p, first, idx, ok)
+		}
+	}
+	if _, ok := g.ShipIndexAt(Position{9, 9}); ok {
+		t.Error("Expected no ship at J10")
+	}
+}
+
+func TestSmallestRemainingShip_smallestSunk(t *testing.T) {
+	g := newTestGame(
+		testShip{4, Position{9, 0}, Horizontal},
+		testShip{2, Position{0, 0}, Horizontal},
+		testShip{3, Position{2, 0}, Vertical},
+	)
+	if size, ok := g.SmallestRemainingShip(); !ok || size != 2 {
+		t.Errorf("Expected smallest remaining ship: %v, got: %v, %v", 2, size, ok)
+	}
+
+	g.Shot(Position{0, 0})
+	g.Shot(Position{0, 1})
+	if size, ok := g.SmallestRemainingShip(); !ok || size != 3 {
+		t.Errorf("Expected smallest remaining ship: %v, got: %v, %v", 3, size, ok)
+	}
+	if _, ok := (&Game{}).SmallestRemainingShip(); ok {
+		t.Error("Expected no remaining ship in an empty game")
+	}
+}
+
+func TestFleetHealthPercent_partialDamage(t *testing.T) {
+	g := newTestGame(
+		testShip{5, Position{0, 0}, Horizontal},
+		testShip{3, Position{2, 0}, Vertical},
+	)
+	if got := g.FleetHealthPercent(); got != 100 {
+		t.Errorf("Expected fleet health: %v, got: %v", 100, got)
+	}
+
+	for _, p := range []Position{{0, 0}, {2, 0}, {3, 0}, {4, 0}, {9, 9}} {
+		g.Shot(p)
+	}
+	if got := g.FleetHealthPercent(); got != 50 {
+		t.Errorf("Expected fleet health: %v, got: %v", 50, got)
+	}
+	if got := (&Game{}).FleetHealthPercent(); got != 0 {
+		t.Errorf("Expected fleet health of empty game: %v, got: %v", 0, got)
+	}
+}
+
+func TestFleetBreakdown_mixedSizes(t *testing.T) {
+	g := newTestGame(
+		testShip{3, Position{0, 0}, Horizontal},
+		testShip{3, Position{2, 0}, Horizontal},
+		testShip{3, Position{4, 0}, Horizontal},
+		testShip{4, Position{9, 0}, Horizontal},
+	)
+	for _, p := range []Position{{9, 0}, {9, 1}, {9, 2}, {9, 3}, {0, 0}} {
+		g.Shot(p)
+	}
+
+	breakdown := g.FleetBreakdown()
+	expected := map[uint8]struct{ Afloat, Sunk int }{
+		3: {Afloat: 3},
+		4: {Sunk: 1},
+	}
+	if len(breakdown) != len(expected) {
+		t.Errorf("Expected breakdown: %v, got: %v", expected, breakdown)
+	}
+	for size, counts := range expected {
+		if breakdown[size] != counts {
+			t.Errorf("Expected counts of size %v: %+v, got: %+v", size, counts, breakdown[size])
+		}
+	}
+}
+
+func TestPerfectGameShots_matchesFleetArea(t *testing.T) {
+	g := newTestGame(
+		testShip{5, Position{0, 0}, Horizontal},
+		testShip{3, Position{2, 0}, Vertical},
+		testShip{1, Position{9, 9}, Horizontal},
+	)
+	if g.PerfectGameShots() != g.FleetArea() || g.FleetArea() != 9 {
+		t.Errorf("Expected perfect game shots equal to fleet area: %v, got: %v", g.FleetArea(), g.PerfectGameShots())
+	}
+
+	for _, p := range g.RemainingTargets() {
+		g.Shot(p)
+	}
+	if !g.IsOver() || g.Stats.ShotsFired != g.PerfectGameShots() {
+		t.Errorf("Expected game to be won after %v shots, got: %v shots, over: %v", g.PerfectGameShots(), g.Stats.ShotsFired, g.IsOver())
+	}
+}
+
+func TestPerfectGameShots_armoredShip(t *testing.T) {
+	g := &Game{}
+	if err := g.FillBoard([]Ship{NewArmoredShip(2, 2)}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := g.PerfectGameShots(); got != 4 || got != len(g.MinimalSolution()) {
+		t.Errorf("Expected perfect game shots: %v, got: %v", 4, got)
+	}
+}
+
+func TestPerfectGameShots_overlappingShips(t *testing.T) {
+	g := newTestGameWithOptions(Options{AllowOverlap: true},
+		testShip{3, Position{0, 0}, Horizontal},
+		testShip{3, Position{0, 2}, Vertical},
+	)
+	if g.FleetArea() != 6 || g.PerfectGameShots() != 5 {
+		t.Errorf("Expected fleet area: 6 and perfect game shots: 5, got: %v and %v", g.FleetArea(), g.PerfectGameShots())
+	}
+}
+
+func TestLayoutNotation(t *testing.T) {
+	g := newTestGame(
+		testShip{5, Position{0, 0}, Vertical},
+		testShip{2, Position{9, 8}, Horizontal},
+		testShip{1, Position{3, 4}, Horizontal},
+	)
+
+	expected := []string{"Carrier A1-E1", "Destroyer J9-J10", "Patrol boat D5"}
+	notation := g.LayoutNotation()
+	if len(notation) != len(expected) {
+		t.Fatalf("Expected notation: %v, got: %v", expected, notation)
+	}
+	for i, e := range expected {
+		if notation[i] != e {
+			t.Errorf("Expected notation: %v, got: %v", e, notation[i])
+		}
+	}
+}
+
+func TestLayoutNotation_wrappedShips(t *testing.T) {
+	g := newTestGameWithOptions(Options{Wrap: true},
+		testShip{4, Position{0, 8}, Horizontal},
+		testShip{3, Position{8, 4}, Vertical},
+	)
+
+	expected := []string{"Battleship A9-A2", "Cruiser I5-A5"}
+	if notation := g.LayoutNotation(); !reflect.DeepEqual(notation, expected) {
+		t.Errorf("Expected notation: %v, got: %v", expected, notation)
+	}
+}
+
+func TestClusterScore_spreadAndClusteredLayouts(t *testing.T) {
+	spread := newTestGame(
+		testShip{3, Position{0, 0}, Horizontal},
+		testShip{3, Position{0, 7}, Horizontal},
+		testShip{3, Position{9, 0}, Horizontal},
+		testShip{3, Position{9, 7}, Horizontal},
+	)
+	clustered := newTestGame(
+		testShip{3, Position{3, 3}, Horizontal},
+		testShip{3, Position{5, 3}, Horizontal},
+		testShip{3, Position{3, 7}, Vertical},
+		testShip{3, Position{7, 3}, Horizontal},
+	)
+
+	if spread.ClusterScore() <= clustered.ClusterScore() {
+		t.Errorf("Expected spread layout to score higher than clustered one, got: %v and %v", spread.ClusterScore(), clustered.ClusterScore())
+	}
+	two := newTestGame(testShip{2, Position{0, 0}, Horizontal}, testShip{2, Position{4, 3}, Horizontal})
+	if got := two.ClusterScore(); got != 5 {
+		t.Errorf("Expected cluster score: %v, got: %v", 5, got)
+	}
+	if got := newTestGame(testShip{2, Position{0, 0}, Horizontal}).ClusterScore(); got != 0 {
+		t.Errorf("Expected cluster score of a single ship: %v, got: %v", 0, got)
+	}
+}
+
+func TestOccupancyRatio(t *testing.T) {
+	data := []struct {
+		ships      []Ship
+		rows, cols int
+		expected   float64
+	}{
+		{StandardFleet(), Rows, Cols, 0.17},
+		{[]Ship{NewShip(5), NewShip(3)}, 4, 4, 0.5},
+		{nil, Rows, Cols, 0},
+		{StandardFleet(), 0, Cols, 0},
+	}
+
+	for _, d := range data {
+		if got := OccupancyRatio(d.ships, d.rows, d.cols); got != d.expected {
+			t.Errorf("Expected occupancy ratio: %v, got: %v for %vx%v board", d.expected, got, d.rows, d.cols)
+		}
+	}
+}
+
+func TestLayoutQualityOK_clusteredLayoutFails(t *testing.T) {
+	clustered := newTestGame(
+		testShip{5, Position{0, 0}, Horizontal},
+		testShip{4, Position{2, 0}, Horizontal},
+		testShip{3, Position{4, 0}, Horizontal},
+		testShip{3, Position{0, 6}, Vertical},
+		testShip{2, Position{4, 4}, Vertical},
+	)
+	if clustered.LayoutQualityOK() {
+		t.Errorf("Expected clustered layout to fail the check, got cluster score: %v", clustered.ClusterScore())
+	}
+	spread := newTestGame(
+		testShip{5, Position{0, 0}, Horizontal},
+		testShip{4, Position{9, 6}, Horizontal},
+		testShip{3, Position{4, 0}, Vertical},
+		testShip{3, Position{0, 9}, Vertical},
+		testShip{2, Position{5, 5}, Vertical},
+	)
+	if !spread.LayoutQualityOK() {
+		t.Errorf("Expected spread layout to pass the check, got cluster score: %v", spread.ClusterScore())
+	}
+}
+
+func TestGenerateGame_rerollPoorLayouts(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		g, err := GenerateGame(Options{RerollPoorLayouts: true}, StandardFleet())
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !g.Playable() || !g.LayoutQualityOK() {
+			t.Errorf("Expected playable game with a good layout, got cluster score: %v", g.ClusterScore())
+		}
+	}
+}
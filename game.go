@@ -1,11 +1,14 @@
 package battleships
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/rand"
 	"regexp"
 	"strconv"
+	"strings"
+	"sync/atomic"
 	"time"
 )
 
@@ -15,9 +18,8 @@ const (
 	// Cols defines number of cols of the game's board
 	Cols = 10
 
-	inputRegex          = "^[A-J](10|[1-9])$"
-	horizontalDirection = 0
-	verticalDirection   = 1
+	inputRegex           = "^[A-J]0*(10|[1-9])$"
+	transposedInputRegex = "^0*(10|[1-9])[A-J]$"
 
 	// EmptySlot defines a field, that doesn't contain any ship and was hit hit so far
 	EmptySlot = '-'
@@ -27,15 +29,56 @@ const (
 	HitShipSlot = 'X'
 	// MissedSlot defines a field without a ship, but that was shot at already
 	MissedSlot = 'O'
+	// SunkShipSlot defines a field with a ship, that has already sunk. It is used only, if sunk ships are marked
+	SunkShipSlot = '#'
+
+	// HitScore defines number of points awarded for hitting a ship
+	HitScore = 2
+	// NearMissScore defines number of points awarded in casual mode for a missed shot next to a ship
+	NearMissScore = 1
+)
+
+// Direction describes, how a ship is placed on the board
+type Direction int
+
+const (
+	// Horizontal describes a ship placed in a single row, starting from its leftmost slot
+	Horizontal Direction = iota
+	// Vertical describes a ship placed in a single column, starting from its topmost slot
+	Vertical
+	// NoDirection is reported for ships, that have no orientation, e.g. single slot ships
+	NoDirection Direction = -1
 )
 
+// ErrAlreadyShot is returned, when a shot is fired at an already shot position and repeated shots are not allowed
+var ErrAlreadyShot = errors.New("Position already shot")
+
+// ErrTurnTimeout is returned, when a shot is fired after the turn's time limit was exceeded. The turn is skipped in such case
+var ErrTurnTimeout = errors.New("Turn time limit exceeded")
+
+// ErrOutOfAmmo is returned, when a shot is fired after all of the shots allowed by the ammo limit were used
+var ErrOutOfAmmo = errors.New("Out of ammo")
+
+// ErrPaused is returned, when a shot is fired while the game is paused
+var ErrPaused = errors.New("Game is paused")
+
+// ErrGameOver is returned, when a shot is fired after the game is over
+var ErrGameOver = errors.New("Game is over")
+
 // PatternMismatch defines error used, when there is not match with the required pattern
 type PatternMismatch struct {
 	input string
+	// transposed tells, that the input was expected in form [1-10][A-J] instead of [A-J][1-10]
+	transposed bool
 }
 
 func (e PatternMismatch) Error() string {
-	return fmt.Sprintf("%v doesn't match the pattern %v", e.input, inputRegex)
+	if e.transposed {
+		return fmt.Sprintf("%v doesn't match the pattern %v: valid columns are 1 to %v and valid rows are A to %c",
+			e.input, transposedInputRegex, Cols, 'A'+Rows-1)
+	}
+	return fmt.Sprintf("%v doesn't match the pattern %v: valid rows are A to %c and valid columns are 1 to %v",
+		e.input, inputRegex, 'A'+Rows-1, Cols)
 }
 
 // Board describes a game board used to store information about the current state of a game.
@@ -46,15 +89,110 @@ func (b *Board) At(p Position) byte {
 	return b[p.row][p.col]
 }
 
+// MarshalJSON encodes the board as an array of rows, each of them being a string of slots
+func (b *Board) MarshalJSON() ([]byte, error) {
+	rows := make([]string, Rows)
+	for i := range b {
+		rows[i] = string(b[i][:])
+	}
+	return json.Marshal(rows)
+}
+
+// UnmarshalJSON decodes the board from the form returned by MarshalJSON
+func (b *Board) UnmarshalJSON(data []byte) error {
+	var rows []string
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return err
+	}
+	if len(rows) != Rows {
+		return fmt.Errorf("Expected %v rows of the board, got %v", Rows, len(rows))
+	}
+	for i, r := range rows {
+		if len(r) != Cols {
+			return fmt.Errorf("Expected %v slots in row %v, got %v", Cols, i+1, len(r))
+		}
+		copy(b[i][:], r)
+	}
+	return nil
+}
+
+// CellState describes state of a single board's slot independently of its text representation
+type CellState int
+
+const (
+	// UnknownState describes a slot with unrecognized value
+	UnknownState CellState = iota
+	// WaterState describes a slot without any ship, that was not shot so far
+	WaterState
+	// ShipState describes a slot with an undamaged ship
+	ShipState
+	// HitState describes a slot with a ship, that is already hit
+	HitState
+	// MissState describes a slot without a ship, that was shot already
+	MissState
+	// SunkState describes a slot with a ship, that has already sunk
+	SunkState
+)
+
+// StateAt returns state of the slot at a specified location in the board
+func (b *Board) StateAt(p Position) CellState {
+	return slotState(b.At(p))
+}
+
+func slotState(v byte) CellState {
+	switch v {
+	case EmptySlot:
+		return WaterState
+	case ShipSlot:
+		return ShipState
+	case HitShipSlot:
+		return HitState
+	case MissedSlot:
+		return MissState
+	case SunkShipSlot:
+		return SunkState
+	}
+	return UnknownState
+}
+
 // Set is a convenient method used to set a new value in the board indexing it with a Position object
 func (b *Board) Set(p Position, val byte) {
 	b[p.row][p.col] = val
 }
 
+// Equal returns true, if both boards contain the same values in all of the slots
+func (b *Board) Equal(other *Board) bool {
+	return *b == *other
+}
+
+// Differences describes all slots, in which the boards differ, one slot per line, e.g. "A1: 'X' != 'S'".
+// Returns empty string for equal boards
+func (b *Board) Differences(other *Board) string {
+	var diffs []string
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			if b[i][j] != other[i][j] {
+				p := Position{row: uint8(i), col: uint8(j)}
+				diffs = append(diffs, fmt.Sprintf("%v: %q != %q", p, b[i][j], other[i][j]))
+			}
+		}
+	}
+	return strings.Join(diffs, "\n")
+}
+
 // Ship describes a single ship object used in the game
 type Ship struct {
-	size   uint8
-	health uint8
+	size      uint8
+	health    uint8
+	direction Direction
+	// armor is number of hits needed to damage each of the ship's slots. Zero value means a single hit
+	armor uint8
+	// shape contains offsets of the ship's slots relative to its position for ships of shapes other than a straight line
+	shape []Position
+	// id identifies a ship created by NewShip, so the same ship isn't placed twice. Zero value means no identity
+	id uint64
+	// cells contains positions of all slots occupied by the ship, once it is placed on the board
+	cells []Position
 }
 
 func (s *Ship) hit() bool {
@@ -67,23 +205,83 @@ func NewShip(size uint8) Ship {
 	return Ship{
 		size:   size,
 		health: size,
+		id:     atomic.AddUint64(&lastShipID, 1),
 	}
 }
 
+// lastShipID is the identity given to the most recently created ship
+var lastShipID uint64
+
+// NewArmoredShip creates a new ship with given size, each slot of which needs to be hit armor times to be damaged
+func NewArmoredShip(size, armor uint8) Ship {
+	s := NewShip(size)
+	s.armor = armor
+	return s
+}
+
 // Game defines an object used to initialize and start a new game
 type Game struct {
 	Stats Statistics
 
-	shipsData   map[Position]*Ship
+	opts Options
+	// ships contains all of the ships placed on the board in order of placement
+	ships []Ship
+	// shipIndex stores, for every slot of the board, index of a ship in ships or -1, if there is no ship
+	shipIndex [Rows][Cols]int
+	// armor stores, for every slot of the board, number of hits the slot still withstands before being damaged
+	armor       [Rows][Cols]uint8
 	board       Board
+	history     []shotRecord
 	initialized bool
+	forfeited   bool
+	// rng is a random generator kept between games by Reuse
+	rng *rand.Rand
+	// startedAt and finishedAt describe, when the game was initialized and when it was over
+	startedAt, finishedAt time.Time
+	// paused is total duration of finished pauses, pausedAt is start of the current pause or zero, if the game isn't paused
+	paused   time.Duration
+	pausedAt time.Time
+	// clock returns current time, time.Now is used if it's nil
+	clock func() time.Time
+	// turnTimeout limits duration of a single turn, there is no limit if it's 0
+	turnTimeout   time.Duration
+	turnStartedAt time.Time
 }
 
 // Statistics defines information about current state of the game
 type Statistics struct {
-	ShotsFired   int
-	InitialShips int
-	SunkShips    int
+	ShotsFired   int `json:"shotsFired"`
+	Hits         int `json:"hits"`
+	InitialShips int `json:"initialShips"`
+	SunkShips    int `json:"sunkShips"`
+	// Score is increased by HitScore for every hit and, in casual mode, by NearMissScore for every near miss
+	Score int `json:"score"`
+	// CurrentStreak is number of consecutive hits since the last shot, that didn't hit
+	CurrentStreak int `json:"currentStreak"`
+	// LongestStreak is the longest number of consecutive hits in the game
+	LongestStreak int `json:"longestStreak"`
+	// HintsUsed is number of ship slots revealed by RevealHint. It isn't affected by undoing shots
+	HintsUsed int `json:"hintsUsed"`
+	// SkippedTurns is number of turns, in which the time limit was exceeded. It isn't affected by undoing shots
+	SkippedTurns int `json:"skippedTurns"`
+	// ScansUsed is number of regions scanned by ScanRegion. It isn't affected by undoing shots
+	ScansUsed int `json:"scansUsed"`
+}
+
+// ShotResult describes outcome of a single shot
+type ShotResult struct {
+	Hit  bool `json:"hit"`
+	Sunk bool `json:"sunk"`
+	// silent is true, if sinking a ship shouldn't be reported to the player
+	silent bool
+}
+
+// PublicResult returns the result, that can be reported to the player. With SilentSinks option Sunk is always false
+func (r ShotResult) PublicResult() ShotResult {
+	if r.silent {
+		r.Sunk = false
+	}
+	return r
 }
 
 // Position describes indexes used to access game's board
@@ -91,81 +289,517 @@ type Position struct {
 	row, col uint8
 }
 
+// String returns the position in the same form, as accepted by ConvertInputToPosition, e.g. A5
+func (p Position) String() string {
+	return fmt.Sprintf("%c%d", 'A'+p.row, p.col+1)
+}
+
+// MarshalText encodes the position in the same form, as returned by String
+func (p Position) MarshalText() ([]byte, error) {
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText decodes position from the form accepted by ConvertInputToPosition
+func (p *Position) UnmarshalText(text []byte) error {
+	pos, err := ConvertInputToPosition(string(text))
+	if err != nil {
+		return err
+	}
+	*p = *pos
+	return nil
+}
+
 // Shot method allows to try to hit a ship at given position.
 // First returned value is true, if a ship was hit. At the same time, if it was the last slot of a ship, true will be returned as second value
-// Method returns error, if called before the game is iniatialized
+// Method returns error, if called before the game is iniatialized, after it is over, if the position was already shot and repeated shots
+// are not allowed, if the turn's time limit was exceeded or while the game is paused
 func (g *Game) Shot(pos Position) (bool, bool, error) {
-	if !g.initialized {
-		return false, false, errors.New("Game not initialized")
+	if err := g.checkShot(pos); err != nil {
+		return false, false, err
+	}
+	if g.turnTimeout > 0 && !g.turnStartedAt.IsZero() && g.now().Sub(g.turnStartedAt) > g.turnTimeout {
+		g.Stats.SkippedTurns++
+		g.turnStartedAt = g.now()
+		return false, false, ErrTurnTimeout
 	}
+	pos = g.resolveTarget(pos)
+	record := shotRecord{pos: pos, slot: g.board.At(pos), stats: g.Stats}
 	g.Stats.ShotsFired++
 
-	if g.board.At(pos) == ShipSlot {
+	hit, sunk := false, false
+	if g.board.At(pos) == ShipSlot && g.armor[pos.row][pos.col] > 0 {
+		g.armor[pos.row][pos.col]--
+		record.armored = true
+		hit = true
+		g.Stats.Hits++
+		g.Stats.Score += HitScore
+	} else if g.board.At(pos) == ShipSlot {
 		g.board.Set(pos, HitShipSlot)
-		s := g.shipsData[pos]
-		sunk := s.hit()
-		if sunk {
-			g.Stats.SunkShips++
+		hit = true
+		g.Stats.Hits++
+		g.Stats.Score += HitScore
+		for _, idx := range g.shipsAt(pos) {
+			record.ships = append(record.ships, idx)
+			if g.ships[idx].hit() {
+				sunk = true
+				g.Stats.SunkShips++
+				if g.opts.MarkSunkShips {
+					record.changes = append(record.changes, g.markSunk(idx)...)
+				}
+				if g.opts.RevealAroundSunk && g.shipsSeparated() && !g.opts.SilentSinks {
+					record.changes = append(record.changes, g.revealAround(g.ships[idx].cells, false)...)
+				}
+			}
 		}
-		return true, sunk, nil
 	} else if g.board.At(pos) == EmptySlot {
 		g.board.Set(pos, MissedSlot)
+		if g.opts.Casual && isNextToShip(g, pos.row, pos.col) {
+			g.Stats.Score += NearMissScore
+		}
+	}
+	if hit && g.opts.RevealDiagonalsOnHit && g.shipsSeparated() {
+		record.changes = append(record.changes, g.revealAround([]Position{pos}, true)...)
+	}
+	if hit {
+		g.Stats.CurrentStreak++
+		if g.Stats.CurrentStreak > g.Stats.LongestStreak {
+			g.Stats.LongestStreak = g.Stats.CurrentStreak
+		}
+	} else {
+		g.Stats.CurrentStreak = 0
+	}
+	g.history = append(g.history, record)
+	g.turnStartedAt = g.now()
+	if g.IsOver() {
+		g.finishedAt = g.now()
 	}
-	return false, false, nil
+	return hit, sunk, nil
+}
+
+// ShotRC shoots at the slot in given row and column counted from 0, like Shot does.
+// Returns error, if the slot is outside of the board or if the shot isn't allowed
+func (g *Game) ShotRC(row, col int) (ShotResult, error) {
+	if row < 0 || row >= Rows || col < 0 || col >= Cols {
+		return ShotResult{}, fmt.Errorf("Slot (%v, %v) is outside of the board", row, col)
+	}
+	hit, sunk, err := g.Shot(Position{row: uint8(row), col: uint8(col)})
+	return g.shotResult(hit, sunk), err
+}
+
+// shotResult returns result of a shot, that hides sinking ships from the player, if the options require it
+func (g *Game) shotResult(hit, sunk bool) ShotResult {
+	return ShotResult{Hit: hit, Sunk: sunk, silent: g.opts.SilentSinks}
+}
+
+// ShotAndRender parses the input like ConvertInputToPosition, shoots at the position and returns outcome of the shot
+// followed by the board with ships hidden and a status line returned by Summary.
+// Returns error, if the input is invalid or the shot isn't allowed
+func (g *Game) ShotAndRender(input string) (string, error) {
+	pos, err := ConvertInputToPosition(input)
+	if err != nil {
+		return "", err
+	}
+	hit, sunk, err := g.Shot(*pos)
+	if err != nil {
+		return "", err
+	}
+	res := g.shotResult(hit, sunk).PublicResult()
+	outcome := "Missed"
+	if res.Sunk {
+		outcome = "Sunk a ship"
+	} else if res.Hit {
+		outcome = "Hit a ship"
+	}
+	shot := g.history[len(g.history)-1].pos
+	return fmt.Sprintf("%v at %v\n%v\n%v\n", outcome, shot, g.TargetBoard(), g.Summary()), nil
+}
+
+// PreviewShot returns result, that Shot would return for given position, without changing the board or statistics
+func (g *Game) PreviewShot(pos Position) (ShotResult, error) {
+	if err := g.checkShot(pos); err != nil {
+		return ShotResult{}, err
+	}
+	pos = g.resolveTarget(pos)
+	res := g.shotResult(false, false)
+	if g.board.At(pos) == ShipSlot {
+		res.Hit = true
+		if g.armor[pos.row][pos.col] > 0 {
+			return res, nil
+		}
+		for _, idx := range g.shipsAt(pos) {
+			if g.ships[idx].health == 1 {
+				res.Sunk = true
+			}
+		}
+	}
+	return res, nil
+}
+
+// resolveTarget returns position, that is actually shot, when aiming at given one. With TutorialFirstHit option
+// the first shot of the game, that would miss, is redirected to the nearest undamaged ship slot
+func (g *Game) resolveTarget(pos Position) Position {
+	if g.opts.TutorialFirstHit && len(g.history) == 0 && g.board.At(pos) != ShipSlot {
+		if targets := g.RemainingTargets(); len(targets) > 0 {
+			return nearestPosition(pos, targets)
+		}
+	}
+	return pos
+}
+
+// checkShot returns error, if a shot at given position isn't allowed in the current state of the game
+func (g *Game) checkShot(pos Position) error {
+	if !g.initialized {
+		return errors.New("Game not initialized")
+	}
+	if !g.pausedAt.IsZero() {
+		return ErrPaused
+	}
+	switch g.Result() {
+	case Won, Forfeited:
+		return ErrGameOver
+	case Lost:
+		return ErrOutOfAmmo
+	}
+	if !g.opts.AllowRepeatShots && isShot(g.board.At(pos)) {
+		return ErrAlreadyShot
+	}
+	return nil
 }
 
 // FillBoard fills randomly the game's board with given ships.
-// After that, the game is fully initialized and ready to be played
-func (g *Game) FillBoard(ships []Ship) {
+// After that, the game is fully initialized and ready to be played.
+// Returns error, if not all of the ships could be placed. The game stays not initialized in such case
+func (g *Game) FillBoard(ships []Ship) error {
+	rand := rand.New(rand.NewSource(time.Now().Unix()))
+
+	return g.fill(ships, func(s Ship) bool {
+		return placeRandomly(g, rand, s)
+	})
+}
+
+// Reuse works the same way as FillBoard, but keeps the game's random generator between calls and reuses memory
+// allocated for the previous game. It is meant for running many games one after another, e.g. in simulations
+func (g *Game) Reuse(ships []Ship) error {
+	if g.rng == nil {
+		g.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	return g.fill(ships, func(s Ship) bool {
+		return placeRandomly(g, g.rng, s)
+	})
+}
+
+// FillBoardSeeded works the same way as FillBoard, but draws the layout from a random generator seeded with seed,
+// so the same seed and ships always give the same layout. Later calls of Reuse continue with the same generator
+func (g *Game) FillBoardSeeded(ships []Ship, seed int64) error {
+	g.rng = rand.New(rand.NewSource(seed))
+	return g.Reuse(ships)
+}
+
+// QuickGame creates a new game with default options filled randomly with StandardFleet, so it is ready to be played
+func QuickGame() (*Game, error) {
+	g := &Game{}
+	if err := g.FillBoard(StandardFleet()); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// PlacedShipCount returns number of distinct ships placed on the board
+func (g *Game) PlacedShipCount() int {
+	return len(g.ships)
+}
+
+// fill clears the board and puts given ships on it one by one using the place function.
+// The game gets initialized only, if all of the ships were placed. Otherwise the board is left empty
+func (g *Game) fill(ships []Ship, place func(s Ship) bool) error {
+	g.clear()
+	g.Stats = Statistics{InitialShips: len(ships)}
+	g.initialized = false
+
+	for _, s := range ships {
+		if !place(s) {
+			g.clear()
+			return fmt.Errorf("Ship of size %v couldn't be placed", s.size)
+		}
+	}
+	if placed := g.PlacedShipCount(); placed != len(ships) {
+		g.clear()
+		return fmt.Errorf("Only %v of %v ships were placed", placed, len(ships))
+	}
+	g.start()
+	return nil
+}
+
+// start initializes the game, so it can be played, and starts measuring its duration
+func (g *Game) start() {
+	g.initialized = true
+	g.startedAt = g.now()
+	g.finishedAt = time.Time{}
+	g.paused, g.pausedAt = 0, time.Time{}
+	g.forfeited = false
+	g.turnStartedAt = g.startedAt
+}
+
+// SetTurnTimeout limits time of every turn. A shot fired after the limit is rejected with ErrTurnTimeout
+// and the turn is skipped. The current turn starts again, when the limit is set. Zero removes the limit
+func (g *Game) SetTurnTimeout(d time.Duration) {
+	g.turnTimeout = d
+	g.turnStartedAt = g.now()
+}
+
+// Duration returns time elapsed since the game was initialized until it was over or, for games still played, until now.
+// Time, when the game was paused, is excluded
+func (g *Game) Duration() time.Duration {
+	if g.startedAt.IsZero() {
+		return 0
+	}
+	return g.end().Sub(g.startedAt) - g.pausedFor()
+}
+
+// Pause stops measuring duration of the game until Resume is called. Shots return ErrPaused in the meantime.
+// It does nothing, if the game isn't played or is already paused
+func (g *Game) Pause() {
+	if g.Playable() && g.pausedAt.IsZero() {
+		g.pausedAt = g.now()
+	}
+}
+
+// Resume continues measuring duration of a paused game. The pause doesn't count towards the turn's time limit either.
+// It does nothing, if the game isn't paused
+func (g *Game) Resume() {
+	if g.pausedAt.IsZero() {
+		return
+	}
+	paused := g.pausedFor()
+	if !g.turnStartedAt.IsZero() {
+		g.turnStartedAt = g.turnStartedAt.Add(paused - g.paused)
+	}
+	g.paused = paused
+	g.pausedAt = time.Time{}
+}
+
+// pausedFor returns total duration of pauses including the current one
+func (g *Game) pausedFor() time.Duration {
+	if end := g.end(); !g.pausedAt.IsZero() && g.pausedAt.Before(end) {
+		return g.paused + end.Sub(g.pausedAt)
+	}
+	return g.paused
+}
+
+// end returns time, when the game was over or current time for games still played
+func (g *Game) end() time.Time {
+	if !g.finishedAt.IsZero() {
+		return g.finishedAt
+	}
+	return g.now()
+}
+
+func (g *Game) now() time.Time {
+	if g.clock != nil {
+		return g.clock()
+	}
+	return time.Now()
+}
+
+// clear removes all of the ships and shots from the board
+func (g *Game) clear() {
 	for i := 0; i < Rows; i++ {
 		for j := 0; j < Cols; j++ {
 			g.board[i][j] = EmptySlot
+			g.shipIndex[i][j] = -1
+			g.armor[i][j] = 0
 		}
 	}
-	g.shipsData = make(map[Position]*Ship)
-	g.Stats.InitialShips = len(ships)
+	g.ships = g.ships[:0]
+	g.history = g.history[:0]
+}
 
-	rand := rand.New(rand.NewSource(time.Now().Unix()))
+// placeRandomly tries to put the ship at random positions. After the number of retries defined in the options fails,
+// it scans all of the positions in order, so the ship is placed, if there is any legal position left for it.
+// With UniformPlacement strategy the ship is placed by placeUniformly instead
+func placeRandomly(g *Game, rand *rand.Rand, s Ship) bool {
+	if g.opts.PlacementStrategy == UniformPlacement {
+		return placeUniformly(g, rand, s)
+	}
+	retries := g.opts.PlacementRetries
+	if retries <= 0 {
+		retries = DefaultPlacementRetries
+	}
 
-	for _, s := range ships {
-		placed := false
-		tries := 0
-		for !placed {
-			tries++
-			direction := rand.Intn(2)
-			maxRow := Rows
-			maxCol := Cols
-			if direction == horizontalDirection {
-				maxRow = Rows - int(s.size) + 1
-			} else {
-				maxCol = Cols - int(s.size) + 1
-			}
-			pos := randomPosition(rand, maxRow, maxCol)
+	for tries := 0; tries < retries; tries++ {
+		// direction is always drawn before position, see randomPosition
+		direction := Direction(rand.Intn(2))
+		maxRow := Rows
+		maxCol := Cols
+		switch {
+		case g.opts.Wrap:
+			// ships wrap around the edges, so they can start at any position
+		case direction == Horizontal:
+			maxCol = Cols - int(s.size) + 1
+		default:
+			maxRow = Rows - int(s.size) + 1
+		}
+		if maxRow <= 0 || maxCol <= 0 {
+			continue
+		}
+		pos := randomPosition(rand, maxRow, maxCol)
 
-			if canPlaceShip(g, s, pos, direction) {
-				placeShip(g, s, pos, direction)
-				placed = true
-			}
-			if tries == 50 {
-				return
+		if canPlaceShip(g, s, pos, direction) {
+			placeShip(g, s, pos, direction)
+			return true
+		}
+	}
+	return placeFirstFit(g, s)
+}
+
+// placeUniformly puts the ship at a placement drawn from all of its legal placements, so each of them is equally likely
+func placeUniformly(g *Game, rand *rand.Rand, s Ship) bool {
+	var candidates []Placement
+	forEachPlacement(s.size, func(pos Position, d Direction) {
+		if canPlaceShip(g, s, pos, d) {
+			candidates = append(candidates, Placement{Ship: s, Position: pos, Direction: d})
+		}
+	})
+	if len(candidates) == 0 {
+		return false
+	}
+	p := candidates[rand.Intn(len(candidates))]
+	placeShip(g, s, p.Position, p.Direction)
+	return true
+}
+
+// placeFirstFit puts the ship at the first legal position found, scanning the board row by row
+func placeFirstFit(g *Game, s Ship) bool {
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			for _, direction := range []Direction{Horizontal, Vertical} {
+				pos := Position{row: uint8(i), col: uint8(j)}
+				if canPlaceShip(g, s, pos, direction) {
+					placeShip(g, s, pos, direction)
+					return true
+				}
 			}
 		}
 	}
-	g.initialized = true
+	return false
 }
 
-// Playable returns true, if there are still ships alive in the current game
+// Initialized returns true, if all of the ships are placed and the game has started, even if it is already over
+func (g *Game) Initialized() bool {
+	return g.initialized
+}
+
+// Playable returns true, if the game is initialized and not over yet
 func (g *Game) Playable() bool {
-	return g.initialized && g.Stats.SunkShips < g.Stats.InitialShips
+	return g.initialized && !g.IsOver()
+}
+
+// Result describes outcome of the game
+type Result int
+
+const (
+	// InProgress describes a game, that isn't over yet, including games not initialized
+	InProgress Result = iota
+	// Won describes a game finished by sinking enough ships
+	Won
+	// Lost describes a game finished by running out of ammo
+	Lost
+	// Forfeited describes a game given up by the player
+	Forfeited
+)
+
+// IsOver returns true, if the game is initialized and it was won, lost by running out of ammo or forfeited
+func (g *Game) IsOver() bool {
+	return g.Result() != InProgress
+}
+
+// Result returns outcome of the game. Sinking enough ships wins the game, even if it was the last shot of the ammo
+func (g *Game) Result() Result {
+	switch {
+	case !g.initialized:
+		return InProgress
+	case g.Won():
+		return Won
+	case g.forfeited:
+		return Forfeited
+	case g.AmmoRemaining() == 0:
+		return Lost
+	}
+	return InProgress
+}
+
+// Forfeit gives up the game, so it is over. It does nothing, if the game isn't played
+func (g *Game) Forfeit() {
+	if g.Playable() {
+		g.forfeited = true
+		g.finishedAt = g.now()
+	}
+}
+
+// Won returns true, if the game is initialized and enough ships were sunk to finish it.
+// By default all ships need to be sunk, unless a lower win threshold is set in the options
+func (g *Game) Won() bool {
+	return g.initialized && g.Stats.SunkShips >= g.winThreshold()
 }
 
-// Board returns deep copy of a game's board. Parametr describes, if ships will be marked on the board or not
+// AmmoRemaining returns number of shots, that can still be fired, if the ammo limit is set in the options.
+// Returns -1 for games without the limit
+func (g *Game) AmmoRemaining() int {
+	if g.opts.AmmoLimit <= 0 {
+		return -1
+	}
+	return maxInt(0, g.opts.AmmoLimit-g.Stats.ShotsFired)
+}
+
+// Summary returns one-line description of the game, e.g. "Won in 47 shots, 68% accuracy, 5/5 ships sunk, 3m12s".
+// With SilentSinks option number of sunk ships is left out, until the game is over
+func (g *Game) Summary() string {
+	if !g.initialized {
+		return "Not started"
+	}
+	accuracy := 0.0
+	if g.Stats.ShotsFired > 0 {
+		accuracy = 100 * float64(g.Stats.Hits) / float64(g.Stats.ShotsFired)
+	}
+	states := map[Result]string{InProgress: "In progress after", Won: "Won in", Lost: "Lost after", Forfeited: "Forfeited after"}
+	state := states[g.Result()]
+	sunk := fmt.Sprintf("%v/%v ships sunk, ", g.Stats.SunkShips, g.Stats.InitialShips)
+	if g.opts.SilentSinks && !g.IsOver() {
+		sunk = ""
+	}
+	return fmt.Sprintf("%v %v shots, %.0f%% accuracy, %v%v", state, g.Stats.ShotsFired,
+		accuracy, sunk, g.Duration().Round(time.Second))
+}
+
+// PublicStats returns statistics, that can be reported to the player. With SilentSinks option number of sunk ships
+// is 0, until the game is over
+func (g *Game) PublicStats() Statistics {
+	stats := g.Stats
+	if g.opts.SilentSinks && !g.IsOver() {
+		stats.SunkShips = 0
+	}
+	return stats
+}
+
+func (g *Game) winThreshold() int {
+	if g.opts.WinThreshold <= 0 || g.opts.WinThreshold > g.Stats.InitialShips {
+		return g.Stats.InitialShips
+	}
+	return g.opts.WinThreshold
+}
+
+// Board returns deep copy of a game's board. Parametr describes, if ships will be marked on the board or not.
+// With SilentSinks option hidden ships are hidden together with sinks, so sunk slots are marked as hit ones,
+// until the game is over
 func (g *Game) Board(hiddenShips bool) *Board {
 	b := &Board{}
+	silent := hiddenShips && g.opts.SilentSinks && !g.IsOver()
 	for i := 0; i < Rows; i++ {
 		for j := 0; j < Cols; j++ {
 			if hiddenShips && g.board[i][j] == ShipSlot {
 				b[i][j] = EmptySlot
+			} else if silent && g.board[i][j] == SunkShipSlot {
+				b[i][j] = HitShipSlot
 			} else {
 				b[i][j] = g.board[i][j]
 			}
@@ -175,23 +809,96 @@ func (g *Game) Board(hiddenShips bool) *Board {
 	return b
 }
 
+// OceanBoard returns copy of the board seen by the player, whose ships are placed in the game:
+// all of the ships together with shots fired at them
+func (g *Game) OceanBoard() *Board {
+	return g.Board(false)
+}
+
+// TargetBoard returns copy of the board seen by the player shooting in the game: only results of the shots,
+// without undamaged ship slots
+func (g *Game) TargetBoard() *Board {
+	return g.Board(true)
+}
+
+// RemainingTargets returns positions of all ship slots, that were not hit so far.
+// Those are exactly the shots still needed to finish the game
+func (g *Game) RemainingTargets() []Position {
+	var targets []Position
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			if g.board[i][j] == ShipSlot {
+				targets = append(targets, Position{row: uint8(i), col: uint8(j)})
+			}
+		}
+	}
+	return targets
+}
+
+// LegalShots returns all positions, that Shot would accept in the current state of the game, ordered row by row.
+// Returns nil, if the game is not playable
+func (g *Game) LegalShots() []Position {
+	if !g.Playable() {
+		return nil
+	}
+	var shots []Position
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			if g.opts.AllowRepeatShots || !isShot(g.board[i][j]) {
+				shots = append(shots, Position{row: uint8(i), col: uint8(j)})
+			}
+		}
+	}
+	return shots
+}
+
+// NearestLegalShot returns the position from LegalShots closest to the requested one, which is the position itself,
+// if it can be shot at. Among equally close positions the first one row by row is returned.
+// Returns error, if there is no legal shot left
+func (g *Game) NearestLegalShot(pos Position) (Position, error) {
+	shots := g.LegalShots()
+	if len(shots) == 0 {
+		return Position{}, errors.New("No legal shots left")
+	}
+	return nearestPosition(pos, shots), nil
+}
+
+// nearestPosition returns the candidate closest to pos, the first one of equally close candidates is preferred.
+// There needs to be at least one candidate
+func nearestPosition(pos Position, candidates []Position) Position {
+	distance := func(p Position) int {
+		dr, dc := int(p.row)-int(pos.row), int(p.col)-int(pos.col)
+		return dr*dr + dc*dc
+	}
+	nearest := candidates[0]
+	for _, p := range candidates[1:] {
+		if distance(p) < distance(nearest) {
+			nearest = p
+		}
+	}
+	return nearest
+}
+
+// randomPosition draws a row first and a column second, so a given seed always yields the same positions.
+// The order is relied upon by layouts reproduced from a seed by FillBoardSeeded and shouldn't be changed
 func randomPosition(rand *rand.Rand, maxR, maxC int) Position {
 	row := rand.Intn(maxR)
 	col := rand.Intn(maxC)
-
 	return Position{row: uint8(row), col: uint8(col)}
 }
 
-func canPlaceShip(g *Game, ship Ship, pos Position, direction int) bool {
-
+func canPlaceShip(g *Game, ship Ship, pos Position, direction Direction) bool {
+	if g.opts.Wrap && (direction == Horizontal && ship.size > Cols || direction == Vertical && ship.size > Rows) {
+		return false
+	}
 	for i := uint8(0); i < ship.size; i++ {
 		switch direction {
-		case horizontalDirection:
-			if !isValidPosition(g, pos.row, pos.col+i) {
+		case Horizontal:
+			if !isValidPosition(g, pos.row, g.wrapCol(pos.col+i)) {
 				return false
 			}
-		case verticalDirection:
-			if !isValidPosition(g, pos.row+i, pos.col) {
+		case Vertical:
+			if !isValidPosition(g, g.wrapRow(pos.row+i), pos.col) {
 				return false
 			}
 		}
@@ -199,8 +906,102 @@ func canPlaceShip(g *Game, ship Ship, pos Position, direction int) bool {
 	return true
 }
 
+// wrapRow returns the row wrapped around the board's edge, if the board is a torus. Otherwise it is returned unchanged
+func (g *Game) wrapRow(row uint8) uint8 {
+	if g.opts.Wrap {
+		return row % Rows
+	}
+	return row
+}
+
+// wrapCol returns the column wrapped around the board's edge, if the board is a torus. Otherwise it is returned unchanged
+func (g *Game) wrapCol(col uint8) uint8 {
+	if g.opts.Wrap {
+		return col % Cols
+	}
+	return col
+}
+
 func isValidPosition(g *Game, row, col uint8) bool {
-	return isWithinBoard(row, col) && !isAnotherShipInNeighbourhood(g, row, col)
+	if !isWithinBoard(row, col) {
+		return false
+	}
+	if g.opts.AllowOverlap {
+		return true
+	}
+	if g.opts.Adjacency == AllowAdjacency {
+		return g.board[row][col] != ShipSlot
+	}
+	return !isAnotherShipInNeighbourhood(g, row, col)
+}
+
+// markSunk marks all of the ship's slots as sunk and returns the changes made
+func (g *Game) markSunk(idx int) []slotChange {
+	var changes []slotChange
+	for _, p := range g.ships[idx].cells {
+		changes = append(changes, slotChange{pos: p, slot: g.board.At(p)})
+		g.board.Set(p, SunkShipSlot)
+	}
+	return changes
+}
+
+// shipsSeparated returns true, if ships can't touch each other, even diagonally, so slots around them are known to be empty
+func (g *Game) shipsSeparated() bool {
+	return g.opts.Adjacency == NoAdjacency && !g.opts.AllowOverlap
+}
+
+// revealAround marks all of the not shot neighbours of the cells, including diagonal ones, as missed and returns
+// the changes made. If diagonalOnly is true, only diagonal neighbours are marked.
+// Under NoAdjacency rule none of them can contain a ship
+func (g *Game) revealAround(cells []Position, diagonalOnly bool) []slotChange {
+	var changes []slotChange
+	for _, p := range cells {
+		for i := p.row + Rows - 1; i <= p.row+Rows+1; i++ {
+			for j := p.col + Cols - 1; j <= p.col+Cols+1; j++ {
+				if diagonalOnly && (i == p.row+Rows || j == p.col+Cols) {
+					continue
+				}
+				n := Position{row: i - Rows, col: j - Cols}
+				if g.opts.Wrap {
+					n = Position{row: i % Rows, col: j % Cols}
+				}
+				if isWithinBoard(n.row, n.col) && g.board.At(n) == EmptySlot {
+					changes = append(changes, slotChange{pos: n, slot: EmptySlot})
+					g.board.Set(n, MissedSlot)
+				}
+			}
+		}
+	}
+	return changes
+}
+
+// shipsAt returns indexes of all ships placed at given position. More than one ship is found only, if overlapping is allowed
+func (g *Game) shipsAt(pos Position) []int {
+	if !g.opts.AllowOverlap {
+		if idx := g.shipIndex[pos.row][pos.col]; idx >= 0 {
+			return []int{idx}
+		}
+		return nil
+	}
+
+	var ships []int
+	for idx, s := range g.ships {
+		for _, c := range s.cells {
+			if c == pos {
+				ships = append(ships, idx)
+				break
+			}
+		}
+	}
+	return ships
+}
+
+func isShipSlot(slot byte) bool {
+	return slot == ShipSlot || slot == HitShipSlot || slot == SunkShipSlot
+}
+
+func isShot(slot byte) bool {
+	return slot == HitShipSlot || slot == MissedSlot || slot == SunkShipSlot
 }
 
 func isWithinBoard(row, col uint8) bool {
@@ -208,14 +1009,21 @@ func isWithinBoard(row, col uint8) bool {
 }
 
 func isAnotherShipInNeighbourhood(g *Game, row, col uint8) bool {
-	minR := max(0, int8(row-1))
-	maxR := min(Rows-1, int8(row+1))
-	minC := max(0, int8(col-1))
-	maxC := min(Cols-1, int8(col+1))
+	return anyInNeighbourhood(g, row, col, func(i, j int) bool {
+		return g.board[i][j] == ShipSlot
+	})
+}
 
-	for i := minR; i <= maxR; i++ {
-		for j := minC; j <= maxC; j++ {
-			if g.board[i][j] == ShipSlot {
+// anyInNeighbourhood returns true, if f returns true for the slot or any of its neighbours, including diagonal ones.
+// If the board is a torus, slots at opposite edges are neighbours
+func anyInNeighbourhood(g *Game, row, col uint8, f func(row, col int) bool) bool {
+	for i := int(row) + Rows - 1; i <= int(row)+Rows+1; i++ {
+		for j := int(col) + Cols - 1; j <= int(col)+Cols+1; j++ {
+			r, c := i-Rows, j-Cols
+			if g.opts.Wrap {
+				r, c = i%Rows, j%Cols
+			}
+			if r >= 0 && r < Rows && c >= 0 && c < Cols && f(r, c) {
 				return true
 			}
 		}
@@ -223,6 +1031,13 @@ func isAnotherShipInNeighbourhood(g *Game, row, col uint8) bool {
 	return false
 }
 
+// isNextToShip returns true, if any of the neighbours of the slot, including diagonal ones, contains a ship
+func isNextToShip(g *Game, row, col uint8) bool {
+	return anyInNeighbourhood(g, row, col, func(i, j int) bool {
+		return g.shipIndex[i][j] >= 0
+	})
+}
+
 func min(x, y int8) int8 {
 	if x < y {
 		return x
@@ -237,31 +1052,46 @@ func max(x, y int8) int8 {
 	return y
 }
 
-func placeShip(g *Game, ship Ship, pos Position, direction int) {
+func placeShip(g *Game, ship Ship, pos Position, direction Direction) {
+	ship.direction = direction
+	if ship.size == 1 {
+		ship.direction = NoDirection
+	}
+	ship.cells = nil
+	if n := len(g.ships); n < cap(g.ships) {
+		// reuse memory of a ship placed in the previous game
+		ship.cells = g.ships[:n+1][n].cells[:0]
+	}
+	g.ships = append(g.ships, ship)
+	idx := len(g.ships) - 1
 	for i := uint8(0); i < ship.size; i++ {
 		switch direction {
-		case horizontalDirection:
-			g.addShip(&ship, Position{row: pos.row, col: pos.col + i})
-		case verticalDirection:
-			g.addShip(&ship, Position{row: pos.row + i, col: pos.col})
+		case Horizontal:
+			g.addShip(idx, Position{row: pos.row, col: g.wrapCol(pos.col + i)})
+		case Vertical:
+			g.addShip(idx, Position{row: g.wrapRow(pos.row + i), col: pos.col})
 		}
 	}
 }
 
-func (g *Game) addShip(ship *Ship, pos Position) {
+func (g *Game) addShip(idx int, pos Position) {
 	g.board.Set(pos, ShipSlot)
-	g.shipsData[pos] = ship
+	g.shipIndex[pos.row][pos.col] = idx
+	if armor := g.ships[idx].armor; armor > 1 && armor-1 > g.armor[pos.row][pos.col] {
+		g.armor[pos.row][pos.col] = armor - 1
+	}
+	g.ships[idx].cells = append(g.ships[idx].cells, pos)
 }
 
 // ConvertInputToPosition allows to convert text input in form [A-Z][1-10] to corresponding (row,column) position.
-// Returns error if the input doesn't match required pattern
+// Leading zeros of the number are ignored, e.g. "A01" is the same as "A1". Returns error if the input doesn't match required pattern
 func ConvertInputToPosition(input string) (*Position, error) {
 	matched, err := regexp.MatchString(inputRegex, input)
 	if err != nil {
 		return nil, err
 	}
 	if !matched {
-		return nil, PatternMismatch{input}
+		return nil, PatternMismatch{input: input}
 	}
 
 	letter := input[0]
@@ -271,3 +1101,48 @@ func ConvertInputToPosition(input string) (*Position, error) {
 
 	return &Position{row: row, col: uint8(col - 1)}, nil
 }
+
+// ConvertTransposedInputToPosition works the same way as ConvertInputToPosition, but accepts input in form [1-10][A-J]
+// used by clients displaying the board transposed, e.g. "5A" maps to the same position as "A5"
+func ConvertTransposedInputToPosition(input string) (*Position, error) {
+	matched, err := regexp.MatchString(transposedInputRegex, input)
+	if err != nil {
+		return nil, err
+	}
+	if !matched {
+		return nil, PatternMismatch{input: input, transposed: true}
+	}
+	last := len(input) - 1
+	return ConvertInputToPosition(input[last:] + input[:last])
+}
+
+// ParseRange returns all positions of an inclusive rectangle described in form "A1:C3" row by row.
+// Corners can be given in any order
+func ParseRange(s string) ([]Position, error) {
+	corners := strings.Split(s, ":")
+	if len(corners) != 2 {
+		return nil, fmt.Errorf("%v doesn't match the range form [A-J][1-10]:[A-J][1-10]", s)
+	}
+	from, err := ConvertInputToPosition(corners[0])
+	if err != nil {
+		return nil, err
+	}
+	to, err := ConvertInputToPosition(corners[1])
+	if err != nil {
+		return nil, err
+	}
+	if from.row > to.row {
+		from.row, to.row = to.row, from.row
+	}
+	if from.col > to.col {
+		from.col, to.col = to.col, from.col
+	}
+
+	var positions []Position
+	for r := from.row; r <= to.row; r++ {
+		for c := from.col; c <= to.col; c++ {
+			positions = append(positions, Position{r, c})
+		}
+	}
+	return positions, nil
+}
@@ -1,11 +1,15 @@
 package battleships
 
 import (
+	"math/rand"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestConvertInputToPosition_noMatch(t *testing.T) {
-	inputs := []string{"A0", "A11", "K1"}
+	inputs := []string{"A0", "A11", "K1", "A00", "A011"}
 
 	for _, input := range inputs {
 		_, err := ConvertInputToPosition(input)
@@ -18,6 +22,16 @@ func TestConvertInputToPosition_noMatch(t *testing.T) {
 	}
 }
 
+func TestPatternMismatch_boundsInMessage(t *testing.T) {
+	_, err := ConvertInputToPosition("A100")
+
+	for _, bound := range []string{"A to J", "1 to 10"} {
+		if err == nil || !strings.Contains(err.Error(), bound) {
+			t.Errorf("Error message doesn't mention %q: %v", bound, err)
+		}
+	}
+}
+
 func TestConvertInputToPosition_positionsReturned(t *testing.T) {
 	data := []struct {
 		in  string
@@ -46,6 +60,50 @@ func TestConvertInputToPosition_positionsReturned(t *testing.T) {
 	}
 }
 
+func TestStateAt_slotsMapped(t *testing.T) {
+	data := []struct {
+		val      byte
+		expected CellState
+	}{
+		{EmptySlot, WaterState},
+		{ShipSlot, ShipState},
+		{HitShipSlot, HitState},
+		{MissedSlot, MissState},
+		{SunkShipSlot, SunkState},
+		{'?', UnknownState},
+		{0, UnknownState},
+	}
+
+	b := Board{}
+	p := Position{3, 4}
+	for _, d := range data {
+		b.Set(p, d.val)
+
+		if got := b.StateAt(p); got != d.expected {
+			t.Errorf("Expected state: %v, got: %v for slot: %q", d.expected, got, d.val)
+		}
+	}
+}
+
+func TestEqual_boardsCompared(t *testing.T) {
+	b := Board{}
+	other := Board{}
+	if !b.Equal(&other) || b.Differences(&other) != "" {
+		t.Errorf("Equal boards reported as different: %v", b.Differences(&other))
+	}
+
+	other.Set(Position{0, 0}, HitShipSlot)
+	other.Set(Position{9, 9}, MissedSlot)
+	b.Set(Position{9, 9}, ShipSlot)
+	if b.Equal(&other) {
+		t.Error("Different boards reported as equal")
+	}
+	expected := "A1: '\\x00' != 'X'\nJ10: 'S' != 'O'"
+	if got := b.Differences(&other); got != expected {
+		t.Errorf("Expected differences: %q, got: %q", expected, got)
+	}
+}
+
 func TestNewShip_helthAndSizeTheSame(t *testing.T) {
 	data := []uint8{1, 2, 3, 4, 5}
 
@@ -142,6 +200,143 @@ func TestFillBoard_boardFilledWithShips(t *testing.T) {
 	}
 }
 
+func TestFill_underPlacedShipsDetected(t *testing.T) {
+	g := Game{}
+	ships := []Ship{NewShip(5), NewShip(4), NewShip(3)}
+	next := Position{0, 0}
+
+	err := g.fill(ships, func(s Ship) bool {
+		if s.size == 4 {
+			return true
+		}
+		placeShip(&g, s, next, Horizontal)
+		next.row += 2
+		return true
+	})
+
+	if err == nil || !strings.Contains(err.Error(), "2 of 3") {
+		t.Errorf("Expected error reporting 2 of 3 ships placed, got: %v", err)
+	}
+	if g.PlacedShipCount() != 0 {
+		t.Errorf("Expected partly placed ships to be cleared, got: %v", g.PlacedShipCount())
+	}
+	if g.initialized {
+		t.Error("Game has been initialized with under-placed fleet")
+	}
+}
+
+func TestCanPlaceShip_exportedDirections(t *testing.T) {
+	g := newTestGame()
+
+	placeShip(g, NewShip(4), Position{0, 6}, Horizontal)
+	if !canPlaceShip(g, NewShip(4), Position{2, 9}, Vertical) {
+		t.Error("Vertical ship couldn't be placed")
+	}
+	placeShip(g, NewShip(4), Position{2, 9}, Vertical)
+
+	expected := []Position{{0, 6}, {0, 7}, {0, 8}, {0, 9}, {2, 9}, {3, 9}, {4, 9}, {5, 9}}
+	for _, p := range expected {
+		if g.board.At(p) != ShipSlot {
+			t.Errorf("No ship placed at %v", p)
+		}
+	}
+	if canPlaceShip(g, NewShip(2), Position{1, 8}, Horizontal) {
+		t.Error("Ship could be placed next to another one")
+	}
+}
+
+func TestPlaceRandomly_scanFallback(t *testing.T) {
+	for seed := int64(0); seed < 20; seed++ {
+		g := newTestGameWithOptions(Options{PlacementRetries: 1},
+			testShip{10, Position{0, 0}, Horizontal},
+			testShip{10, Position{2, 0}, Horizontal},
+			testShip{10, Position{4, 0}, Horizontal},
+			testShip{10, Position{6, 0}, Horizontal},
+			testShip{6, Position{9, 0}, Horizontal},
+		)
+
+		if !placeRandomly(g, rand.New(rand.NewSource(seed)), NewShip(3)) {
+			t.Errorf("Ship not placed, although there is a legal position, seed: %v", seed)
+			continue
+		}
+		s := g.ships[len(g.ships)-1]
+		if s.cells[0] != (Position{8, 7}) && s.cells[0] != (Position{9, 7}) {
+			t.Errorf("Ship placed at unexpected position: %v", s.cells)
+		}
+	}
+}
+
+func TestReuse_uniformPlacement(t *testing.T) {
+	g := NewGameWithOptions(Options{PlacementStrategy: UniformPlacement})
+	g.rng = rand.New(rand.NewSource(1))
+	if err := g.Reuse(StandardFleet()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if g.PlacedShipCount() != len(StandardFleet()) || g.HasOverlaps() || g.ViolatesAdjacency() {
+		t.Errorf("Expected legal layout of the whole fleet, got:\n%v", g.Board(false))
+	}
+
+	// a single slot ship has the same chance to be placed in every slot
+	var counts [Rows][Cols]int
+	const layouts = 5000
+	for seed := int64(0); seed < layouts; seed++ {
+		g.rng = rand.New(rand.NewSource(seed))
+		g.Reuse([]Ship{NewShip(1)})
+		c := g.ships[0].cells[0]
+		counts[c.row][c.col]++
+	}
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			if n := counts[i][j]; n < layouts/Rows/Cols/2 || n > 2*layouts/Rows/Cols {
+				t.Errorf("Expected about %v ships placed at %v, got: %v", layouts/Rows/Cols, Position{uint8(i), uint8(j)}, n)
+			}
+		}
+	}
+}
+
+func TestPlaceRandomly_noLegalPosition(t *testing.T) {
+	g := newTestGame(
+		testShip{10, Position{0, 0}, Horizontal},
+		testShip{10, Position{2, 0}, Horizontal},
+		testShip{10, Position{4, 0}, Horizontal},
+		testShip{10, Position{6, 0}, Horizontal},
+		testShip{10, Position{8, 0}, Horizontal},
+	)
+
+	if placeRandomly(g, rand.New(rand.NewSource(1)), NewShip(2)) {
+		t.Error("Ship placed on a full board")
+	}
+	if placeRandomly(g, rand.New(rand.NewSource(1)), NewShip(11)) {
+		t.Error("Ship larger than the board placed")
+	}
+}
+
+func TestPlacedShipCount_distinctShipsCounted(t *testing.T) {
+	g := Game{}
+	ships := []Ship{NewShip(5), NewShip(4), NewShip(4)}
+
+	if err := g.FillBoard(ships); err != nil {
+		t.Errorf("Error has been returned %v", err)
+	}
+	if g.PlacedShipCount() != len(ships) {
+		t.Errorf("Expected number of placed ships: %v, got: %v", len(ships), g.PlacedShipCount())
+	}
+}
+
+func TestQuickGame_playable(t *testing.T) {
+	g, err := QuickGame()
+
+	if err != nil {
+		t.Fatalf("Error has been returned %v", err)
+	}
+	if !g.Playable() {
+		t.Error("Game is not playable")
+	}
+	if g.PlacedShipCount() != len(StandardFleet()) {
+		t.Errorf("Expected number of placed ships: %v, got: %v", len(StandardFleet()), g.PlacedShipCount())
+	}
+}
+
 func TestPlayable(t *testing.T) {
 	data := []struct {
 		initialized         bool
@@ -165,3 +360,1071 @@ func TestPlayable(t *testing.T) {
 		}
 	}
 }
+
+func TestInitialized_beforeAndAfterFillBoard(t *testing.T) {
+	g := &Game{}
+	if g.Initialized() {
+		t.Error("Expected new game not to be initialized")
+	}
+	if err := g.FillBoard([]Ship{NewShip(1)}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !g.Initialized() {
+		t.Error("Expected filled game to be initialized")
+	}
+	g.Shot(g.RemainingTargets()[0])
+	if !g.Initialized() || g.Playable() {
+		t.Errorf("Expected finished game to stay initialized, but not playable, got: %v, %v", g.Initialized(), g.Playable())
+	}
+}
+
+func TestRemainingTargets_countMatchesNotHitSlots(t *testing.T) {
+	g := newTestGame(
+		testShip{5, Position{0, 0}, Horizontal},
+		testShip{4, Position{2, 3}, Vertical},
+	)
+	shots := []Position{{0, 0}, {0, 1}, {2, 3}, {9, 9}}
+
+	for _, s := range shots {
+		g.Shot(s)
+	}
+
+	expected := 5 + 4 - 3
+	targets := g.RemainingTargets()
+	if len(targets) != expected {
+		t.Errorf("Expected number of remaining targets: %v, got: %v", expected, len(targets))
+	}
+	for _, p := range targets {
+		if g.board.At(p) != ShipSlot {
+			t.Errorf("Position %v doesn't contain an undamaged ship", p)
+		}
+	}
+}
+
+func TestNewGameWithOptions_optionsTakeEffect(t *testing.T) {
+	opts := Options{Adjacency: AllowAdjacency, AllowRepeatShots: true}
+	g := newTestGameWithOptions(opts, testShip{5, Position{0, 0}, Horizontal})
+
+	if g.opts != opts {
+		t.Errorf("Expected options: %v, got: %v", opts, g.opts)
+	}
+	if !canPlaceShip(g, NewShip(4), Position{1, 0}, Horizontal) {
+		t.Error("Ship touching another one couldn't be placed, although adjacency is allowed")
+	}
+	if canPlaceShip(g, NewShip(4), Position{0, 1}, Vertical) {
+		t.Error("Ship overlapping another one could be placed")
+	}
+	for i := 0; i < 2; i++ {
+		if _, _, err := g.Shot(Position{5, 5}); err != nil {
+			t.Errorf("Repeated shot returned error: %v", err)
+		}
+	}
+	if g.Stats.ShotsFired != 2 {
+		t.Errorf("Expected number of shots fired: %v, got: %v", 2, g.Stats.ShotsFired)
+	}
+}
+
+func TestNewGameWithOptions_defaultRules(t *testing.T) {
+	g := newTestGameWithOptions(Options{}, testShip{5, Position{0, 0}, Horizontal})
+
+	if canPlaceShip(g, NewShip(4), Position{1, 0}, Horizontal) {
+		t.Error("Ship touching another one could be placed with default rules")
+	}
+	g.Shot(Position{5, 5})
+	if _, _, err := g.Shot(Position{5, 5}); err != ErrAlreadyShot {
+		t.Errorf("Expected error: %v, got: %v", ErrAlreadyShot, err)
+	}
+	if g.Stats.ShotsFired != 1 {
+		t.Errorf("Expected number of shots fired: %v, got: %v", 1, g.Stats.ShotsFired)
+	}
+}
+
+func BenchmarkShipLookup_indexGrid(b *testing.B) {
+	g := &Game{}
+	g.FillBoard([]Ship{NewShip(5), NewShip(4), NewShip(4), NewShip(3), NewShip(2)})
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		health := 0
+		for i := 0; i < Rows; i++ {
+			for j := 0; j < Cols; j++ {
+				if idx := g.shipIndex[i][j]; idx >= 0 {
+					health += int(g.ships[idx].health)
+				}
+			}
+		}
+	}
+}
+
+// BenchmarkShipLookup_map measures the previous representation, where every slot was mapped to a ship pointer
+func BenchmarkShipLookup_map(b *testing.B) {
+	g := &Game{}
+	g.FillBoard([]Ship{NewShip(5), NewShip(4), NewShip(4), NewShip(3), NewShip(2)})
+	shipsData := make(map[Position]*Ship)
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			if idx := g.shipIndex[i][j]; idx >= 0 {
+				shipsData[Position{uint8(i), uint8(j)}] = &g.ships[idx]
+			}
+		}
+	}
+
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		health := 0
+		for i := 0; i < Rows; i++ {
+			for j := 0; j < Cols; j++ {
+				if s, ok := shipsData[Position{uint8(i), uint8(j)}]; ok {
+					health += int(s.health)
+				}
+			}
+		}
+	}
+}
+
+func TestIsOver_winThreshold(t *testing.T) {
+	g := newTestGameWithOptions(Options{WinThreshold: 2},
+		testShip{1, Position{0, 0}, Horizontal},
+		testShip{1, Position{2, 2}, Horizontal},
+		testShip{1, Position{4, 4}, Horizontal},
+	)
+
+	g.Shot(Position{0, 0})
+	if g.IsOver() || !g.Playable() {
+		t.Error("Game is over after sinking a single ship")
+	}
+	g.Shot(Position{4, 4})
+	if !g.IsOver() || g.Playable() {
+		t.Error("Game is not over after sinking 2 of 3 ships")
+	}
+}
+
+func TestIsOver_allShipsByDefault(t *testing.T) {
+	g := newTestGame(
+		testShip{1, Position{0, 0}, Horizontal},
+		testShip{1, Position{2, 2}, Horizontal},
+	)
+
+	g.Shot(Position{0, 0})
+	if g.IsOver() {
+		t.Error("Game is over with a ship still alive")
+	}
+	g.Shot(Position{2, 2})
+	if !g.IsOver() {
+		t.Error("Game is not over after sinking all of the ships")
+	}
+}
+
+func TestShot_casualScoring(t *testing.T) {
+	ship := testShip{3, Position{4, 4}, Horizontal}
+	shots := []Position{{4, 4}, {3, 3}, {5, 7}, {0, 0}, {4, 7}}
+	data := []struct {
+		casual   bool
+		expected int
+	}{
+		{false, HitScore},
+		{true, HitScore + 3*NearMissScore},
+	}
+
+	for _, d := range data {
+		g := newTestGameWithOptions(Options{Casual: d.casual}, ship)
+		for _, s := range shots {
+			g.Shot(s)
+		}
+
+		if g.Stats.Score != d.expected {
+			t.Errorf("Expected score: %v, got: %v in casual mode: %v", d.expected, g.Stats.Score, d.casual)
+		}
+	}
+}
+
+func TestShot_casualScoringAcrossWrappedEdge(t *testing.T) {
+	g := newTestGameWithOptions(Options{Casual: true, Wrap: true}, testShip{2, Position{0, 0}, Vertical})
+	g.Shot(Position{9, 9})
+	if g.Stats.Score != NearMissScore {
+		t.Errorf("Expected score for a near miss across the edge: %v, got: %v", NearMissScore, g.Stats.Score)
+	}
+}
+
+func TestShot_sunkShipMarked(t *testing.T) {
+	g := newTestGameWithOptions(Options{MarkSunkShips: true}, testShip{2, Position{0, 0}, Horizontal})
+	g.Shot(Position{0, 0})
+	g.Shot(Position{0, 1})
+
+	for _, p := range []Position{{0, 0}, {0, 1}} {
+		if g.board.At(p) != SunkShipSlot {
+			t.Errorf("Slot %v not marked as sunk: %q", p, g.board.At(p))
+		}
+	}
+	g.Undo()
+	if g.board.At(Position{0, 0}) != HitShipSlot || g.board.At(Position{0, 1}) != ShipSlot {
+		t.Errorf("Undo didn't restore the ship's slots: %v", g.board)
+	}
+}
+
+func TestShot_neighboursOfSunkShipRevealed(t *testing.T) {
+	data := []struct {
+		opts   Options
+		missed int
+	}{
+		{Options{RevealAroundSunk: true}, 4},
+		{Options{RevealAroundSunk: true, Adjacency: AllowAdjacency}, 1},
+		{Options{RevealAroundSunk: true, AllowOverlap: true}, 1},
+		{Options{}, 1},
+	}
+
+	for _, d := range data {
+		g := newTestGameWithOptions(d.opts, testShip{2, Position{0, 0}, Horizontal}, testShip{2, Position{5, 5}, Horizontal})
+		for _, p := range []Position{{1, 0}, {0, 0}, {0, 1}} {
+			g.Shot(p)
+		}
+
+		if got := len(g.LegalShots()); got != Rows*Cols-2-d.missed {
+			t.Errorf("Expected number of missed slots: %v, got board:\n%v for options: %+v", d.missed, g.Board(false), d.opts)
+		}
+		if g.Stats.ShotsFired != 3 {
+			t.Errorf("Expected number of shots: %v, got: %v", 3, g.Stats.ShotsFired)
+		}
+		g.Undo()
+		if g.board.At(Position{1, 1}) != EmptySlot || g.board.At(Position{1, 0}) != MissedSlot {
+			t.Errorf("Undo didn't restore neighbours of the ship: %v", g.board)
+		}
+	}
+}
+
+func TestShot_diagonalsOfHitRevealed(t *testing.T) {
+	g := newTestGameWithOptions(Options{RevealDiagonalsOnHit: true}, testShip{3, Position{4, 3}, Horizontal})
+	g.Shot(Position{4, 4})
+
+	for _, p := range []Position{{3, 3}, {3, 5}, {5, 3}, {5, 5}} {
+		if g.board.At(p) != MissedSlot {
+			t.Errorf("Expected diagonal neighbour %v marked as missed, got: %c", p, g.board.At(p))
+		}
+	}
+	for _, p := range []Position{{3, 4}, {5, 4}} {
+		if g.board.At(p) != EmptySlot {
+			t.Errorf("Expected orthogonal neighbour %v not shot, got: %c", p, g.board.At(p))
+		}
+	}
+	if g.Stats.ShotsFired != 1 {
+		t.Errorf("Expected number of shots: %v, got: %v", 1, g.Stats.ShotsFired)
+	}
+	g.Undo()
+	if g.board.At(Position{3, 3}) != EmptySlot {
+		t.Errorf("Undo didn't restore diagonal neighbours: %v", g.board)
+	}
+
+	g.opts.Adjacency = AllowAdjacency
+	g.Shot(Position{4, 4})
+	if g.board.At(Position{3, 3}) != EmptySlot {
+		t.Error("Expected diagonal neighbours not marked, when ships can touch")
+	}
+}
+
+func TestShot_diagonalsNotRevealedWithOverlap(t *testing.T) {
+	g := newTestGameWithOptions(Options{RevealDiagonalsOnHit: true, AllowOverlap: true},
+		testShip{2, Position{0, 0}, Horizontal},
+		testShip{2, Position{1, 2}, Horizontal},
+	)
+	g.Shot(Position{0, 1})
+
+	for _, p := range []Position{{1, 0}, {1, 2}} {
+		if g.board.At(p) == MissedSlot {
+			t.Errorf("Expected diagonal neighbour %v not marked, when adjacency isn't checked", p)
+		}
+	}
+}
+
+func TestShot_atSunkSlot(t *testing.T) {
+	data := []struct {
+		allowRepeatShots bool
+		err              error
+	}{
+		{false, ErrAlreadyShot},
+		{true, nil},
+	}
+
+	for _, d := range data {
+		g := newTestGameWithOptions(Options{MarkSunkShips: true, AllowRepeatShots: d.allowRepeatShots},
+			testShip{2, Position{0, 0}, Horizontal},
+			testShip{2, Position{5, 5}, Horizontal},
+		)
+		g.Shot(Position{0, 0})
+		g.Shot(Position{0, 1})
+		board := g.board
+		sunk := g.Stats.SunkShips
+
+		hit, sunkNow, err := g.Shot(Position{0, 0})
+		if err != d.err {
+			t.Errorf("Expected error: %v, got: %v", d.err, err)
+		}
+		if hit || sunkNow {
+			t.Errorf("Shot at a sunk slot reported hit: %v, sunk: %v", hit, sunkNow)
+		}
+		if g.board != board || g.Stats.SunkShips != sunk {
+			t.Error("Shot at a sunk slot modified the game")
+		}
+	}
+}
+
+func TestReuse_boardRefilled(t *testing.T) {
+	g := Game{}
+	ships := []Ship{NewShip(5), NewShip(4), NewShip(4)}
+	g.Reuse(ships)
+	g.Shot(Position{0, 0})
+
+	if err := g.Reuse(ships); err != nil {
+		t.Fatalf("Error has been returned %v", err)
+	}
+	if !g.Playable() || g.PlacedShipCount() != len(ships) || len(g.history) != 0 {
+		t.Errorf("Game not refilled properly, ships: %v, history: %v", g.PlacedShipCount(), len(g.history))
+	}
+	if len(g.RemainingTargets()) != 13 {
+		t.Errorf("Expected number of ship slots: %v, got: %v", 13, len(g.RemainingTargets()))
+	}
+}
+
+func TestReuse_afterWonGame(t *testing.T) {
+	g := Game{}
+	ships := StandardFleet()
+	g.Reuse(ships)
+	for _, p := range g.MinimalSolution() {
+		g.Shot(p)
+	}
+	if !g.Won() {
+		t.Fatal("Expected the first game to be won")
+	}
+
+	if err := g.Reuse(ships); err != nil {
+		t.Fatalf("Error has been returned %v", err)
+	}
+	if !g.Playable() {
+		t.Error("Expected the next game to be playable")
+	}
+	if expected := (Statistics{InitialShips: len(ships)}); g.Stats != expected {
+		t.Errorf("Expected statistics: %+v, got: %+v", expected, g.Stats)
+	}
+}
+
+func BenchmarkFillBoard(b *testing.B) {
+	g := Game{}
+	ships := StandardFleet()
+
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		g.FillBoard(ships)
+	}
+}
+
+func BenchmarkReuse(b *testing.B) {
+	g := Game{}
+	ships := StandardFleet()
+
+	b.ReportAllocs()
+	for n := 0; n < b.N; n++ {
+		g.Reuse(ships)
+	}
+}
+
+func TestShot_overlappingShipsDamaged(t *testing.T) {
+	g := newTestGameWithOptions(Options{AllowOverlap: true},
+		testShip{3, Position{2, 2}, Horizontal},
+		testShip{2, Position{2, 3}, Vertical},
+	)
+	if g.PlacedShipCount() != 2 {
+		t.Fatalf("Expected number of placed ships: %v, got: %v", 2, g.PlacedShipCount())
+	}
+
+	hit, sunk, _ := g.Shot(Position{2, 3})
+	if !hit || sunk {
+		t.Errorf("Expected hit without sinking, got hit: %v, sunk: %v", hit, sunk)
+	}
+	if g.ships[0].health != 2 || g.ships[1].health != 1 {
+		t.Errorf("Expected both ships damaged, health: %v, %v", g.ships[0].health, g.ships[1].health)
+	}
+	hit, sunk, _ = g.Shot(Position{3, 3})
+	if !hit || !sunk || g.Stats.SunkShips != 1 {
+		t.Errorf("Expected the vertical ship to sink, got hit: %v, sunk: %v", hit, sunk)
+	}
+
+	g.UndoN(2)
+	if g.ships[0].health != 3 || g.ships[1].health != 2 {
+		t.Errorf("Undo didn't restore health: %v, %v", g.ships[0].health, g.ships[1].health)
+	}
+}
+
+func TestLegalShots_shotSlotsExcluded(t *testing.T) {
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal})
+	shots := []Position{{0, 0}, {4, 4}, {9, 9}}
+	for _, s := range shots {
+		g.Shot(s)
+	}
+
+	legal := g.LegalShots()
+	if len(legal) != Rows*Cols-len(shots) {
+		t.Errorf("Expected number of legal shots: %v, got: %v", Rows*Cols-len(shots), len(legal))
+	}
+	for _, p := range legal {
+		for _, s := range shots {
+			if p == s {
+				t.Errorf("Already shot position %v reported as legal", p)
+			}
+		}
+	}
+
+	g.Shot(Position{0, 1})
+	if g.LegalShots() != nil {
+		t.Error("Legal shots reported for a finished game")
+	}
+}
+
+func TestShot_hitStreaks(t *testing.T) {
+	g := newTestGame(testShip{5, Position{0, 0}, Horizontal})
+	data := []struct {
+		pos              Position
+		current, longest int
+	}{
+		{Position{0, 0}, 1, 1},
+		{Position{0, 1}, 2, 2},
+		{Position{5, 5}, 0, 2},
+		{Position{0, 2}, 1, 2},
+	}
+
+	for _, d := range data {
+		g.Shot(d.pos)
+
+		if g.Stats.CurrentStreak != d.current || g.Stats.LongestStreak != d.longest {
+			t.Errorf("Expected streaks: %v, %v, got: %v, %v after shot at %v",
+				d.current, d.longest, g.Stats.CurrentStreak, g.Stats.LongestStreak, d.pos)
+		}
+	}
+}
+
+type testShip struct {
+	size      uint8
+	pos       Position
+	direction Direction
+}
+
+// newTestGame creates an initialized game with ships placed at known positions
+func newTestGame(ships ...testShip) *Game {
+	return newTestGameWithOptions(Options{}, ships...)
+}
+
+// newTestGameWithOptions creates an initialized game with given options and ships placed at known positions
+func newTestGameWithOptions(opts Options, ships ...testShip) *Game {
+	g := NewGameWithOptions(opts)
+	g.clear()
+	for _, s := range ships {
+		placeShip(g, NewShip(s.size), s.pos, s.direction)
+	}
+	g.Stats.InitialShips = len(ships)
+	g.start()
+	return g
+}
+
+func TestSummary_describesGame(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal})
+	g.clock = func() time.Time { return now }
+	g.start()
+
+	shots := []struct {
+		pos      Position
+		elapsed  time.Duration
+		expected string
+	}{
+		{Position{5, 5}, 10 * time.Second, "In progress after 1 shots, 0% accuracy, 0/1 ships sunk, 10s"},
+		{Position{0, 0}, 20 * time.Second, "In progress after 2 shots, 50% accuracy, 0/1 ships sunk, 20s"},
+		{Position{0, 1}, 3*time.Minute + 12*time.Second, "Won in 3 shots, 67% accuracy, 1/1 ships sunk, 3m12s"},
+	}
+	start := now
+	for _, s := range shots {
+		now = start.Add(s.elapsed)
+		g.Shot(s.pos)
+		if summary := g.Summary(); summary != s.expected {
+			t.Errorf("Expected summary: %v, got: %v", s.expected, summary)
+		}
+	}
+
+	now = now.Add(time.Hour)
+	if summary := g.Summary(); summary != shots[2].expected {
+		t.Errorf("Expected summary of finished game to stay: %v, got: %v", shots[2].expected, summary)
+	}
+	if summary := (&Game{}).Summary(); summary != "Not started" {
+		t.Errorf("Expected summary: Not started, got: %v", summary)
+	}
+}
+
+func TestShot_afterGameOver(t *testing.T) {
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal})
+	g.Shot(Position{0, 0})
+	g.Shot(Position{0, 1})
+	stats, board := g.Stats, g.board
+
+	hit, sunk, err := g.Shot(Position{5, 5})
+	if err != ErrGameOver {
+		t.Errorf("Expected error: %v, got: %v", ErrGameOver, err)
+	}
+	if hit || sunk {
+		t.Errorf("Expected no-op result, got: hit %v, sunk %v", hit, sunk)
+	}
+	if g.Stats != stats {
+		t.Errorf("Expected stats: %v, got: %v", stats, g.Stats)
+	}
+	if g.board != board {
+		t.Errorf("Shot after the game was over changed the board: %v", g.board.Differences(&board))
+	}
+}
+
+func TestParseRange(t *testing.T) {
+	data := []struct {
+		input    string
+		expected []Position
+	}{
+		{"B2:B2", []Position{{1, 1}}},
+		{"C1:C3", []Position{{2, 0}, {2, 1}, {2, 2}}},
+		{"B2:A1", []Position{{0, 0}, {0, 1}, {1, 0}, {1, 1}}},
+	}
+
+	for _, d := range data {
+		positions, err := ParseRange(d.input)
+		if err != nil {
+			t.Errorf("Unexpected error for %v: %v", d.input, err)
+		}
+		if !reflect.DeepEqual(positions, d.expected) {
+			t.Errorf("Expected positions: %v, got: %v for %v", d.expected, positions, d.input)
+		}
+	}
+}
+
+func TestParseRange_malformed(t *testing.T) {
+	inputs := []string{"A1", "A1:C3:D4", "A1:K3", "A1-C3", ""}
+
+	for _, input := range inputs {
+		if positions, err := ParseRange(input); err == nil {
+			t.Errorf("Expected error for %v, got positions: %v", input, positions)
+		}
+	}
+}
+
+func TestPlaceRandomly_seedGivesGoldenLayout(t *testing.T) {
+	golden := []string{
+		"--------S-",
+		"--SSS---S-",
+		"--------S-",
+		"SSSS------",
+		"----------",
+		"--------S-",
+		"--------S-",
+		"--------S-",
+		"--------S-",
+		"----SS--S-",
+	}
+	g := &Game{}
+	if err := g.FillBoardSeeded(StandardFleet(), 42); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for i, row := range golden {
+		if got := string(g.board[i][:]); got != row {
+			t.Errorf("Expected row %c of seeded layout: %v, got: %v", 'A'+i, row, got)
+		}
+	}
+}
+
+func TestConvertTransposedInputToPosition(t *testing.T) {
+	inputs := []string{"A1", "B7", "J10", "C10"}
+
+	for _, input := range inputs {
+		expected, _ := ConvertInputToPosition(input)
+		transposed := input[1:] + input[:1]
+		pos, err := ConvertTransposedInputToPosition(transposed)
+		if err != nil {
+			t.Errorf("Unexpected error for %v: %v", transposed, err)
+			continue
+		}
+		if *pos != *expected {
+			t.Errorf("Expected position: %v, got: %v for %v", *expected, *pos, transposed)
+		}
+	}
+	for _, input := range []string{"A1", "0A", "11A", "1K"} {
+		_, err := ConvertTransposedInputToPosition(input)
+		if _, ok := err.(PatternMismatch); !ok {
+			t.Errorf("No PatternMismatch for input %v - err: %v", input, err)
+			continue
+		}
+		for _, bound := range []string{"A to J", "1 to 10"} {
+			if !strings.Contains(err.Error(), bound) {
+				t.Errorf("Error message doesn't mention %q: %v", bound, err)
+			}
+		}
+	}
+}
+
+func TestPreviewShot_gameUnchanged(t *testing.T) {
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal}, testShip{3, Position{5, 5}, Vertical})
+	g.Shot(Position{0, 0})
+	data := []struct {
+		pos      Position
+		expected ShotResult
+		err      error
+	}{
+		{Position{0, 1}, ShotResult{Hit: true, Sunk: true}, nil},
+		{Position{5, 5}, ShotResult{Hit: true}, nil},
+		{Position{9, 9}, ShotResult{}, nil},
+		{Position{0, 0}, ShotResult{}, ErrAlreadyShot},
+	}
+
+	board, stats, history := g.board, g.Stats, len(g.history)
+	for _, d := range data {
+		res, err := g.PreviewShot(d.pos)
+		if res != d.expected || err != d.err {
+			t.Errorf("Expected result: %v and error: %v, got: %v and %v for %v", d.expected, d.err, res, err, d.pos)
+		}
+	}
+	if g.board != board || g.Stats != stats || len(g.history) != history {
+		t.Error("Preview of a shot changed the game")
+	}
+	if g.ships[0].health != 1 {
+		t.Errorf("Expected health of previewed ship: %v, got: %v", 1, g.ships[0].health)
+	}
+}
+
+func TestOceanAndTargetBoard_twoPlayers(t *testing.T) {
+	// every player's ships are placed in a separate game, in which the opponent shoots
+	first := newTestGame(testShip{2, Position{0, 0}, Horizontal})
+	second := newTestGame(testShip{2, Position{5, 5}, Vertical})
+	second.Shot(Position{5, 5})
+	second.Shot(Position{0, 0})
+	first.Shot(Position{9, 9})
+
+	ocean, target := first.OceanBoard(), second.TargetBoard()
+	data := []struct {
+		board    *Board
+		pos      Position
+		expected byte
+	}{
+		{ocean, Position{0, 0}, ShipSlot},
+		{ocean, Position{0, 1}, ShipSlot},
+		{ocean, Position{9, 9}, MissedSlot},
+		{target, Position{5, 5}, HitShipSlot},
+		{target, Position{6, 5}, EmptySlot},
+		{target, Position{0, 0}, MissedSlot},
+	}
+
+	for _, d := range data {
+		if v := d.board.At(d.pos); v != d.expected {
+			t.Errorf("Expected slot %v: %c, got: %c", d.pos, d.expected, v)
+		}
+	}
+}
+
+func TestPublicResult_silentSinks(t *testing.T) {
+	data := []struct {
+		opts     Options
+		expected ShotResult
+	}{
+		{Options{SilentSinks: true}, ShotResult{Hit: true}},
+		{Options{}, ShotResult{Hit: true, Sunk: true}},
+	}
+
+	for _, d := range data {
+		g := newTestGameWithOptions(d.opts, testShip{1, Position{0, 0}, Horizontal}, testShip{1, Position{5, 5}, Horizontal})
+		res, err := g.ShotRC(0, 0)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !res.Sunk || g.Stats.SunkShips != 1 {
+			t.Errorf("Expected sink recorded internally, got: %v, sunk ships: %v", res, g.Stats.SunkShips)
+		}
+		if public := res.PublicResult(); public.Hit != d.expected.Hit || public.Sunk != d.expected.Sunk {
+			t.Errorf("Expected public result: %+v, got: %+v for options: %+v", d.expected, public, d.opts)
+		}
+	}
+}
+
+func TestShotAndRender_silentSinkNotRevealed(t *testing.T) {
+	g := newTestGameWithOptions(Options{SilentSinks: true}, testShip{1, Position{0, 0}, Horizontal}, testShip{1, Position{5, 5}, Horizontal})
+	out, err := g.ShotAndRender("A1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(out, "Hit a ship at A1") || strings.Contains(out, "sunk") || strings.Contains(out, "Sunk") {
+		t.Errorf("Expected output not revealing the sink, got:\n%v", out)
+	}
+	if stats := g.PublicStats(); stats.SunkShips != 0 || g.Stats.SunkShips != 1 {
+		t.Errorf("Expected sink counted only internally, got public: %v, internal: %v", stats.SunkShips, g.Stats.SunkShips)
+	}
+}
+
+func TestTargetBoard_silentSinkNotRevealed(t *testing.T) {
+	g := newTestGameWithOptions(Options{SilentSinks: true, MarkSunkShips: true, RevealAroundSunk: true},
+		testShip{2, Position{0, 0}, Horizontal},
+		testShip{1, Position{5, 5}, Horizontal},
+	)
+	g.Shot(Position{0, 0})
+	g.Shot(Position{0, 1})
+
+	b := g.TargetBoard()
+	for _, p := range []Position{{0, 0}, {0, 1}} {
+		if b.At(p) != HitShipSlot {
+			t.Errorf("Expected slot %v of the sunk ship shown as hit: %c, got: %c", p, HitShipSlot, b.At(p))
+		}
+	}
+	if v := b.At(Position{1, 1}); v != EmptySlot {
+		t.Errorf("Expected slot around the sunk ship not revealed, got: %c", v)
+	}
+	if strings.Contains(b.String(), string(SunkShipSlot)) {
+		t.Errorf("Expected no sunk slots on the board, got:\n%v", b)
+	}
+}
+
+func TestShotAndRender_outcomeAndBoard(t *testing.T) {
+	g := newTestGame(testShip{2, Position{1, 2}, Horizontal})
+	data := []struct {
+		input, outcome string
+	}{
+		{"B3", "Hit a ship at B3"},
+		{"A1", "Missed at A1"},
+		{"B4", "Sunk a ship at B4"},
+	}
+
+	for _, d := range data {
+		out, err := g.ShotAndRender(d.input)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if !strings.HasPrefix(out, d.outcome) || !strings.Contains(out, g.TargetBoard().String()) {
+			t.Errorf("Expected outcome: %q and the updated board, got:\n%v", d.outcome, out)
+		}
+	}
+	if _, err := g.ShotAndRender("K1"); err == nil {
+		t.Error("Expected error for invalid input")
+	}
+}
+
+func TestShotRC_rowAndColumnInts(t *testing.T) {
+	g := newTestGame(testShip{2, Position{3, 4}, Horizontal})
+	data := []struct {
+		row, col int
+		expected ShotResult
+		fails    bool
+	}{
+		{3, 4, ShotResult{Hit: true}, false},
+		{9, 9, ShotResult{}, false},
+		{3, 5, ShotResult{Hit: true, Sunk: true}, false},
+		{-1, 0, ShotResult{}, true},
+		{0, Cols, ShotResult{}, true},
+	}
+
+	for _, d := range data {
+		res, err := g.ShotRC(d.row, d.col)
+		if res != d.expected || (err != nil) != d.fails {
+			t.Errorf("Expected result: %v and failure: %v, got: %v and %v for (%v, %v)", d.expected, d.fails, res, err, d.row, d.col)
+		}
+	}
+	if g.Stats.ShotsFired != 3 {
+		t.Errorf("Expected number of shots: %v, got: %v", 3, g.Stats.ShotsFired)
+	}
+}
+
+func TestShot_armoredShipSurvivesFirstHit(t *testing.T) {
+	g := &Game{}
+	g.PlaceShip(NewArmoredShip(2, 2), Position{0, 0}, Horizontal)
+	g.Ready()
+
+	hit, sunk, err := g.Shot(Position{0, 0})
+	if !hit || sunk || err != nil {
+		t.Errorf("Expected hit without sinking, got: hit %v, sunk %v, error %v", hit, sunk, err)
+	}
+	if g.board.At(Position{0, 0}) != ShipSlot || g.ships[0].health != 2 {
+		t.Errorf("Expected armored slot to stay undamaged, got slot: %c, health: %v", g.board.At(Position{0, 0}), g.ships[0].health)
+	}
+	if res, _ := g.PreviewShot(Position{0, 1}); res.Sunk {
+		t.Error("Expected preview of a shot at armored slot not to sink the ship")
+	}
+
+	g.Shot(Position{0, 0})
+	if g.board.At(Position{0, 0}) != HitShipSlot || g.ships[0].health != 1 {
+		t.Errorf("Expected slot damaged by the second hit, got slot: %c, health: %v", g.board.At(Position{0, 0}), g.ships[0].health)
+	}
+
+	g.UndoN(2)
+	if g.armor[0][0] != 1 || g.board.At(Position{0, 0}) != ShipSlot {
+		t.Errorf("Expected armor restored by undo: %v, got: %v", 1, g.armor[0][0])
+	}
+	for i := 0; i < 3; i++ {
+		g.Shot(Position{0, 1})
+	}
+	g.Shot(Position{0, 0})
+	if g.IsOver() {
+		t.Error("Game is over, although the armored slot was hit only once")
+	}
+}
+
+func TestShot_turnTimeout(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal})
+	g.clock = func() time.Time { return now }
+	g.SetTurnTimeout(10 * time.Second)
+
+	now = now.Add(5 * time.Second)
+	if _, _, err := g.Shot(Position{5, 5}); err != nil {
+		t.Errorf("Unexpected error for a quick turn: %v", err)
+	}
+
+	now = now.Add(11 * time.Second)
+	if hit, _, err := g.Shot(Position{0, 0}); err != ErrTurnTimeout || hit {
+		t.Errorf("Expected error: %v without hit, got: %v, hit %v", ErrTurnTimeout, err, hit)
+	}
+	if g.Stats.SkippedTurns != 1 || g.Stats.ShotsFired != 1 || g.board.At(Position{0, 0}) != ShipSlot {
+		t.Errorf("Expected skipped turn without a shot, got stats: %+v", g.Stats)
+	}
+
+	now = now.Add(3 * time.Second)
+	if hit, _, err := g.Shot(Position{0, 0}); err != nil || !hit {
+		t.Errorf("Expected hit in the next turn, got: %v, hit %v", err, hit)
+	}
+}
+
+func TestConvertInputToPosition_leadingZeros(t *testing.T) {
+	data := []struct {
+		in  string
+		out Position
+	}{
+		{"A01", Position{0, 0}},
+		{"C09", Position{2, 8}},
+		{"A010", Position{0, 9}},
+	}
+
+	for _, d := range data {
+		pos, err := ConvertInputToPosition(d.in)
+		if err != nil {
+			t.Errorf("Unexpected error for %v: %v", d.in, err)
+			continue
+		}
+		if *pos != d.out {
+			t.Errorf("Expected position: %v, got: %v for %v", d.out, *pos, d.in)
+		}
+	}
+}
+
+func TestWrap_shipWrapsAroundEdge(t *testing.T) {
+	g := NewGameWithOptions(Options{Wrap: true})
+	if err := g.PlaceShip(NewShip(4), Position{2, 8}, Horizontal); err != nil {
+		t.Fatalf("Error has been returned %v", err)
+	}
+	if err := g.PlaceShip(NewShip(2), Position{0, 3}, Vertical); err != nil {
+		t.Fatalf("Error has been returned %v", err)
+	}
+	g.Ready()
+
+	expected := []Position{{2, 8}, {2, 9}, {2, 0}, {2, 1}}
+	if !reflect.DeepEqual(g.ships[0].cells, expected) {
+		t.Errorf("Expected cells of wrapped ship: %v, got: %v", expected, g.ships[0].cells)
+	}
+	if hit, _, err := g.Shot(Position{2, 1}); !hit || err != nil {
+		t.Errorf("Expected hit at wrapped slot, got: hit %v, error %v", hit, err)
+	}
+	if g.ships[0].health != 3 {
+		t.Errorf("Expected health of wrapped ship: %v, got: %v", 3, g.ships[0].health)
+	}
+	if canPlaceShip(g, NewShip(2), Position{9, 2}, Horizontal) {
+		t.Error("Ship touching another one across the edge could be placed")
+	}
+	if plain := newTestGame(); canPlaceShip(plain, NewShip(4), Position{2, 8}, Horizontal) {
+		t.Error("Ship crossing the edge could be placed without wrapping")
+	}
+}
+
+func TestPause_durationExcludesPause(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal})
+	g.clock = func() time.Time { return now }
+	g.start()
+
+	now = now.Add(time.Minute)
+	g.Shot(Position{0, 0})
+	g.Pause()
+	now = now.Add(10 * time.Minute)
+	if d := g.Duration(); d != time.Minute {
+		t.Errorf("Expected duration of paused game: %v, got: %v", time.Minute, d)
+	}
+
+	g.Resume()
+	now = now.Add(30 * time.Second)
+	g.Shot(Position{0, 1})
+	now = now.Add(time.Hour)
+	if d := g.Duration(); d != 90*time.Second {
+		t.Errorf("Expected duration excluding the pause: %v, got: %v", 90*time.Second, d)
+	}
+}
+
+func TestResume_pauseExcludedFromTurnTimeout(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal})
+	g.clock = func() time.Time { return now }
+	g.start()
+	g.SetTurnTimeout(time.Minute)
+
+	now = now.Add(30 * time.Second)
+	g.Pause()
+	now = now.Add(10 * time.Minute)
+	g.Resume()
+	now = now.Add(20 * time.Second)
+	if _, _, err := g.Shot(Position{0, 0}); err != nil {
+		t.Errorf("Expected shot within the time limit excluding the pause, got: %v", err)
+	}
+	if g.Stats.SkippedTurns != 0 {
+		t.Errorf("Expected number of skipped turns: %v, got: %v", 0, g.Stats.SkippedTurns)
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, _, err := g.Shot(Position{0, 1}); err != ErrTurnTimeout {
+		t.Errorf("Expected error: %v, got: %v", ErrTurnTimeout, err)
+	}
+}
+
+func TestShot_rejectedWhilePaused(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal})
+	g.clock = func() time.Time { return now }
+	g.start()
+	g.SetTurnTimeout(time.Minute)
+
+	now = now.Add(30 * time.Second)
+	g.Pause()
+	now = now.Add(10 * time.Minute)
+	if _, _, err := g.Shot(Position{0, 0}); err != ErrPaused {
+		t.Errorf("Expected error: %v, got: %v", ErrPaused, err)
+	}
+	if g.Stats.ShotsFired != 0 {
+		t.Errorf("Expected no shots fired while paused, got: %v", g.Stats.ShotsFired)
+	}
+
+	g.Resume()
+	if !g.turnStartedAt.Before(now) {
+		t.Errorf("Expected the turn to start before now: %v, got: %v", now, g.turnStartedAt)
+	}
+	now = now.Add(31 * time.Second)
+	if _, _, err := g.Shot(Position{0, 0}); err != ErrTurnTimeout {
+		t.Errorf("Expected error: %v, got: %v", ErrTurnTimeout, err)
+	}
+}
+
+func TestShot_outOfAmmo(t *testing.T) {
+	g := newTestGameWithOptions(Options{AmmoLimit: 3}, testShip{2, Position{0, 0}, Horizontal})
+
+	for i, p := range []Position{{0, 0}, {5, 5}, {6, 6}} {
+		if g.AmmoRemaining() != 3-i {
+			t.Errorf("Expected remaining ammo: %v, got: %v", 3-i, g.AmmoRemaining())
+		}
+		if _, _, err := g.Shot(p); err != nil {
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+
+	if _, _, err := g.Shot(Position{0, 1}); err != ErrOutOfAmmo {
+		t.Errorf("Expected error: %v, got: %v", ErrOutOfAmmo, err)
+	}
+	if !g.IsOver() || g.Won() || g.Playable() {
+		t.Errorf("Expected lost game, got: over %v, won %v", g.IsOver(), g.Won())
+	}
+	if g.Stats.ShotsFired != 3 || g.AmmoRemaining() != 0 {
+		t.Errorf("Expected 3 shots fired and no ammo left, got: %v and %v", g.Stats.ShotsFired, g.AmmoRemaining())
+	}
+	if !strings.HasPrefix(g.Summary(), "Lost after 3 shots") {
+		t.Errorf("Expected summary of lost game, got: %v", g.Summary())
+	}
+	if unlimited := newTestGame(testShip{2, Position{0, 0}, Horizontal}); unlimited.AmmoRemaining() != -1 {
+		t.Errorf("Expected remaining ammo of unlimited game: %v, got: %v", -1, unlimited.AmmoRemaining())
+	}
+}
+
+func TestResult_endConditions(t *testing.T) {
+	ship := testShip{2, Position{0, 0}, Horizontal}
+	won := newTestGame(ship)
+	won.Shot(Position{0, 0})
+	won.Shot(Position{0, 1})
+	lost := newTestGameWithOptions(Options{AmmoLimit: 1}, ship)
+	lost.Shot(Position{5, 5})
+	forfeited := newTestGame(ship)
+	forfeited.Shot(Position{0, 0})
+	forfeited.Forfeit()
+	wonWithLastShot := newTestGameWithOptions(Options{AmmoLimit: 2}, ship)
+	wonWithLastShot.Shot(Position{0, 0})
+	wonWithLastShot.Shot(Position{0, 1})
+	inProgress := newTestGame(ship)
+	inProgress.Shot(Position{0, 0})
+
+	data := []struct {
+		name     string
+		g        *Game
+		expected Result
+	}{
+		{"won", won, Won},
+		{"lost", lost, Lost},
+		{"forfeited", forfeited, Forfeited},
+		{"won with the last shot", wonWithLastShot, Won},
+		{"in progress", inProgress, InProgress},
+		{"not initialized", &Game{}, InProgress},
+	}
+	for _, d := range data {
+		if got := d.g.Result(); got != d.expected {
+			t.Errorf("Expected result: %v, got: %v for %v game", d.expected, got, d.name)
+		}
+		if d.g.IsOver() != (d.expected != InProgress) {
+			t.Errorf("Expected over: %v, got: %v for %v game", d.expected != InProgress, d.g.IsOver(), d.name)
+		}
+	}
+	if _, _, err := forfeited.Shot(Position{0, 1}); err != ErrGameOver {
+		t.Errorf("Expected error: %v, got: %v", ErrGameOver, err)
+	}
+}
+
+func TestNearestLegalShot(t *testing.T) {
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal})
+	for _, p := range []Position{{4, 4}, {3, 4}, {4, 3}, {4, 5}, {0, 0}} {
+		g.Shot(p)
+	}
+	data := []struct {
+		in, expected Position
+	}{
+		{Position{4, 4}, Position{5, 4}},
+		{Position{0, 0}, Position{0, 1}},
+		{Position{7, 7}, Position{7, 7}},
+	}
+
+	for _, d := range data {
+		got, err := g.NearestLegalShot(d.in)
+		if err != nil || got != d.expected {
+			t.Errorf("Expected nearest legal shot: %v, got: %v, error: %v for %v", d.expected, got, err, d.in)
+		}
+	}
+	if _, err := (&Game{}).NearestLegalShot(Position{0, 0}); err == nil {
+		t.Error("Expected error for not initialized game")
+	}
+}
+
+func TestShot_tutorialFirstHit(t *testing.T) {
+	for _, target := range []Position{{9, 9}, {0, 0}, {4, 1}} {
+		g := newTestGameWithOptions(Options{TutorialFirstHit: true}, testShip{3, Position{0, 0}, Vertical})
+
+		if res, err := g.PreviewShot(target); !res.Hit || err != nil {
+			t.Errorf("Expected preview of the first shot at %v to hit, got: %v, error %v", target, res, err)
+		}
+		if hit, _, err := g.Shot(target); !hit || err != nil {
+			t.Errorf("Expected the first shot at %v to hit, got: hit %v, error %v", target, hit, err)
+		}
+		if hit, _, _ := g.Shot(Position{9, 9}); hit {
+			t.Error("Expected the second shot not to be redirected")
+		}
+	}
+
+	g := newTestGameWithOptions(Options{TutorialFirstHit: true}, testShip{3, Position{0, 0}, Vertical})
+	g.Shot(Position{4, 1})
+	if h := g.History(); h[0].Position != (Position{2, 0}) {
+		t.Errorf("Expected the first shot redirected to: %v, got: %v", Position{2, 0}, h[0].Position)
+	}
+}
@@ -0,0 +1,212 @@
+package battleships
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// RevealHint returns a random undamaged ship slot without shooting at it and counts it in HintsUsed statistic.
+// Returns false, if the game is not playable or there is no undamaged ship slot left
+func (g *Game) RevealHint(rng *rand.Rand) (Position, bool) {
+	targets := g.RemainingTargets()
+	if !g.Playable() || len(targets) == 0 {
+		return Position{}, false
+	}
+	g.Stats.HintsUsed++
+	return targets[rng.Intn(len(targets))], true
+}
+
+// HitCells returns positions of all ship slots, that were already hit
+func (g *Game) HitCells() []Position {
+	var hits []Position
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			if g.board[i][j] == HitShipSlot {
+				hits = append(hits, Position{row: uint8(i), col: uint8(j)})
+			}
+		}
+	}
+	return hits
+}
+
+// TargetCandidates returns not shot positions, that are horizontal or vertical neighbours of any hit slot.
+// Those are natural next targets, as the hit ships may continue there. Positions are ordered row by row
+func (g *Game) TargetCandidates() []Position {
+	var candidate [Rows][Cols]bool
+	for _, h := range g.HitCells() {
+		for _, n := range orthogonalNeighbours(h) {
+			if !isShot(g.board.At(n)) {
+				candidate[n.row][n.col] = true
+			}
+		}
+	}
+
+	var candidates []Position
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			if candidate[i][j] {
+				candidates = append(candidates, Position{row: uint8(i), col: uint8(j)})
+			}
+		}
+	}
+	return candidates
+}
+
+// CouldPlace returns true, if a ship of given size could still be placed at given position and direction
+// considering only information visible to the player: missed shots block the placement,
+// while not shot and hit slots don't
+func (g *Game) CouldPlace(size uint8, pos Position, direction Direction) bool {
+	return couldPlace(&g.board, size, pos, direction)
+}
+
+func couldPlace(b *Board, size uint8, pos Position, direction Direction) bool {
+	if size == 0 {
+		return false
+	}
+	for i := uint8(0); i < size; i++ {
+		row, col := pos.row, pos.col
+		switch direction {
+		case Horizontal:
+			col += i
+		case Vertical:
+			row += i
+		default:
+			return false
+		}
+		if !isWithinBoard(row, col) || b[row][col] == MissedSlot {
+			return false
+		}
+	}
+	return true
+}
+
+// ScanRegion returns number of slots occupied by ships, including damaged ones, in a window of the board
+// and counts the scan in ScansUsed statistic. The window is clipped to the board like in StringRegion.
+// Returns error, if the game is not playable or the window doesn't cover any slot of the board
+func (g *Game) ScanRegion(top, left, h, w int) (int, error) {
+	if !g.Playable() {
+		return 0, errors.New("Game is not playable")
+	}
+	bottom, right := minInt(Rows, top+h), minInt(Cols, left+w)
+	top, left = maxInt(0, top), maxInt(0, left)
+	if top >= bottom || left >= right {
+		return 0, fmt.Errorf("Region %vx%v at (%v, %v) is outside of the board", h, w, top, left)
+	}
+
+	count := 0
+	for i := top; i < bottom; i++ {
+		for j := left; j < right; j++ {
+			if isShipSlot(g.board[i][j]) {
+				count++
+			}
+		}
+	}
+	g.Stats.ScansUsed++
+	return count, nil
+}
+
+// MinimalSolution returns the shortest sequence of shots winning the game knowing the layout of ships.
+// Ships needing the least shots are sunk first, ships are shot at slot by slot, armored slots are repeated.
+// Returns nil, if the game is not playable
+func (g *Game) MinimalSolution() []Position {
+	if !g.Playable() {
+		return nil
+	}
+	shots := func(s Ship) int {
+		n := 0
+		for _, c := range s.cells {
+			if g.board.At(c) == ShipSlot {
+				n += int(g.armor[c.row][c.col]) + 1
+			}
+		}
+		return n
+	}
+	var order []int
+	for idx, s := range g.ships {
+		if s.health > 0 {
+			order = append(order, idx)
+		}
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return shots(g.ships[order[i]]) < shots(g.ships[order[j]])
+	})
+
+	var solution []Position
+	var planned [Rows][Cols]bool
+	for _, idx := range order[:g.winThreshold()-g.Stats.SunkShips] {
+		for _, c := range g.ships[idx].cells {
+			if g.board.At(c) != ShipSlot || planned[c.row][c.col] {
+				continue
+			}
+			planned[c.row][c.col] = true
+			for i := 0; i <= int(g.armor[c.row][c.col]); i++ {
+				solution = append(solution, c)
+			}
+		}
+	}
+	return solution
+}
+
+// DeadCells returns not shot positions, that can't contain any of the remaining ships, because the gaps between
+// missed shots and sunk ships around them are shorter than the smallest remaining ship. Positions are ordered row by row.
+// Returns nil, if the ships wrap around the edges or are shaped, as the gaps don't limit them then
+func (g *Game) DeadCells() []Position {
+	smallest, ok := g.SmallestRemainingShip()
+	if !ok || !g.straightPlacements() {
+		return nil
+	}
+
+	var dead []Position
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			p := Position{row: uint8(i), col: uint8(j)}
+			if isShot(g.board.At(p)) {
+				continue
+			}
+			if fittingLength(&g.board, p, Horizontal) < int(smallest) && fittingLength(&g.board, p, Vertical) < int(smallest) {
+				dead = append(dead, p)
+			}
+		}
+	}
+	return dead
+}
+
+// fittingLength returns length of the longest ship in given direction, that could cover given position
+// without crossing missed shots or sunk ships
+func fittingLength(b *Board, pos Position, direction Direction) int {
+	blocked := func(row, col int) bool {
+		return !isWithinBoard(uint8(row), uint8(col)) || b[row][col] == MissedSlot || b[row][col] == SunkShipSlot
+	}
+	dr, dc := 0, 1
+	if direction == Vertical {
+		dr, dc = 1, 0
+	}
+
+	length := 1
+	for r, c := int(pos.row)+dr, int(pos.col)+dc; !blocked(r, c); r, c = r+dr, c+dc {
+		length++
+	}
+	for r, c := int(pos.row)-dr, int(pos.col)-dc; !blocked(r, c); r, c = r-dr, c-dc {
+		length++
+	}
+	return length
+}
+
+func orthogonalNeighbours(p Position) []Position {
+	var neighbours []Position
+	if p.row > 0 {
+		neighbours = append(neighbours, Position{row: p.row - 1, col: p.col})
+	}
+	if p.row < Rows-1 {
+		neighbours = append(neighbours, Position{row: p.row + 1, col: p.col})
+	}
+	if p.col > 0 {
+		neighbours = append(neighbours, Position{row: p.row, col: p.col - 1})
+	}
+	if p.col < Cols-1 {
+		neighbours = append(neighbours, Position{row: p.row, col: p.col + 1})
+	}
+	return neighbours
+}
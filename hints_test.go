@@ -0,0 +1,173 @@
+package battleships
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestTargetCandidates_neighboursOfHit(t *testing.T) {
+	data := []struct {
+		ship     testShip
+		shots    []Position
+		expected []Position
+	}{
+		{testShip{3, Position{4, 3}, Horizontal}, []Position{{4, 4}}, []Position{{3, 4}, {4, 3}, {4, 5}, {5, 4}}},
+		{testShip{3, Position{4, 3}, Horizontal}, []Position{{3, 4}, {4, 4}}, []Position{{4, 3}, {4, 5}, {5, 4}}},
+		{testShip{2, Position{0, 0}, Horizontal}, []Position{{0, 0}}, []Position{{0, 1}, {1, 0}}},
+		{testShip{2, Position{8, 9}, Vertical}, []Position{{9, 9}}, []Position{{8, 9}, {9, 8}}},
+		{testShip{2, Position{8, 9}, Vertical}, []Position{{5, 5}}, nil},
+	}
+
+	for _, d := range data {
+		g := newTestGame(d.ship)
+		for _, s := range d.shots {
+			g.Shot(s)
+		}
+
+		got := g.TargetCandidates()
+		if len(got) != len(d.expected) {
+			t.Errorf("Expected candidates: %v, got: %v", d.expected, got)
+			continue
+		}
+		for i := range got {
+			if got[i] != d.expected[i] {
+				t.Errorf("Expected candidates: %v, got: %v", d.expected, got)
+				break
+			}
+		}
+	}
+}
+
+func TestCouldPlace_missBlocksPlacement(t *testing.T) {
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal})
+	g.Shot(Position{1, 4})
+	g.Shot(Position{0, 0})
+
+	data := []struct {
+		size      uint8
+		pos       Position
+		direction Direction
+		expected  bool
+	}{
+		{4, Position{1, 1}, Horizontal, false},
+		{4, Position{1, 5}, Horizontal, true},
+		{3, Position{1, 1}, Horizontal, true},
+		{4, Position{0, 0}, Horizontal, true},
+		{4, Position{0, 4}, Vertical, false},
+		{4, Position{2, 4}, Vertical, true},
+		{4, Position{0, 7}, Horizontal, false},
+		{4, Position{7, 0}, Vertical, false},
+	}
+
+	for _, d := range data {
+		if got := g.CouldPlace(d.size, d.pos, d.direction); got != d.expected {
+			t.Errorf("Expected: %v, got: %v for data: %v", d.expected, got, d)
+		}
+	}
+}
+
+func TestRevealHint_revealsShip(t *testing.T) {
+	g := newTestGame(testShip{3, Position{0, 0}, Horizontal}, testShip{2, Position{5, 5}, Vertical})
+	g.Shot(Position{0, 0})
+	rng := rand.New(rand.NewSource(1))
+
+	for i := 1; i <= 10; i++ {
+		pos, ok := g.RevealHint(rng)
+		if !ok {
+			t.Fatal("Expected a hint to be revealed")
+		}
+		if g.board.At(pos) != ShipSlot {
+			t.Errorf("Revealed position %v doesn't contain an undamaged ship", pos)
+		}
+		if g.Stats.HintsUsed != i || g.Stats.ShotsFired != 1 {
+			t.Errorf("Expected hints used: %v and shots fired: 1, got: %v", i, g.Stats)
+		}
+	}
+
+	g.Undo()
+	if g.Stats.HintsUsed != 10 {
+		t.Errorf("Expected hints used to stay after undo: %v, got: %v", 10, g.Stats.HintsUsed)
+	}
+	if _, ok := (&Game{}).RevealHint(rng); ok {
+		t.Error("Hint has been revealed in not initialized game")
+	}
+}
+
+func TestDeadCells_gapSmallerThanShips(t *testing.T) {
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal}, testShip{3, Position{5, 5}, Vertical})
+	g.Shot(Position{8, 0})
+	g.Shot(Position{9, 1})
+
+	dead := g.DeadCells()
+	if len(dead) != 1 || dead[0] != (Position{9, 0}) {
+		t.Errorf("Expected dead cells: %v, got: %v", []Position{{9, 0}}, dead)
+	}
+
+	g.Shot(Position{9, 3})
+	if dead := g.DeadCells(); len(dead) != 1 {
+		t.Errorf("Expected a two slot gap to stay alive, got dead cells: %v", dead)
+	}
+}
+
+func TestMinimalSolution_winsInExactlyThatManyShots(t *testing.T) {
+	data := []struct {
+		opts     Options
+		expected int
+	}{
+		{Options{}, 5 + 3 + 2 - 1},
+		{Options{WinThreshold: 2}, 3 + 2 - 1},
+	}
+
+	for _, d := range data {
+		g := newTestGameWithOptions(d.opts,
+			testShip{5, Position{0, 0}, Horizontal},
+			testShip{3, Position{2, 0}, Vertical},
+			testShip{2, Position{9, 8}, Horizontal},
+		)
+		g.Shot(Position{9, 8})
+		g.Shot(Position{5, 5})
+
+		solution := g.MinimalSolution()
+		if len(solution) != d.expected {
+			t.Errorf("Expected solution of length: %v, got: %v for options: %+v", d.expected, solution, d.opts)
+		}
+		for _, p := range solution {
+			if _, _, err := g.Shot(p); err != nil {
+				t.Errorf("Shot from the solution at %v returned error: %v", p, err)
+			}
+		}
+		if !g.IsOver() {
+			t.Errorf("Expected the game to be won after replaying the solution for options: %+v", d.opts)
+		}
+	}
+}
+
+func TestScanRegion_countsShipSlots(t *testing.T) {
+	g := newTestGame(testShip{3, Position{0, 0}, Horizontal}, testShip{2, Position{5, 5}, Vertical})
+	g.Shot(Position{0, 1})
+	data := []struct {
+		top, left, h, w int
+		expected        int
+	}{
+		{0, 0, 2, 2, 2},
+		{0, 0, Rows, Cols, 5},
+		{5, 4, 1, 3, 1},
+		{-2, 8, 4, 5, 0},
+	}
+
+	for i, d := range data {
+		count, err := g.ScanRegion(d.top, d.left, d.h, d.w)
+		if err != nil || count != d.expected {
+			t.Errorf("Expected count: %v, got: %v, error: %v for %+v", d.expected, count, err, d)
+		}
+		if g.Stats.ScansUsed != i+1 {
+			t.Errorf("Expected scans used: %v, got: %v", i+1, g.Stats.ScansUsed)
+		}
+	}
+	if _, err := g.ScanRegion(Rows, 0, 2, 2); err == nil {
+		t.Error("Expected error for a region outside of the board")
+	}
+	if g.Stats.ScansUsed != len(data) || g.Stats.ShotsFired != 1 {
+		t.Errorf("Expected only successful scans counted and no shots fired, got: %+v", g.Stats)
+	}
+}
@@ -0,0 +1,194 @@
+package battleships
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"time"
+)
+
+// ShotRecord describes a single shot fired in the game
+type ShotRecord struct {
+	// Ordinal is a number of the shot in the game, starting from 1
+	Ordinal  int      `json:"ordinal"`
+	Position Position `json:"position"`
+	Hit      bool     `json:"hit"`
+	Sunk     bool     `json:"sunk"`
+}
+
+// shotRecord describes a single shot stored in the game's history together with everything needed to revert it
+type shotRecord struct {
+	pos Position
+	// slot is a value of the board's slot before the shot
+	slot byte
+	// ships are indexes of all ships damaged by the shot
+	ships []int
+	// stats are game's statistics before the shot
+	stats Statistics
+	// changes describe other slots, that were modified as a result of the shot
+	changes []slotChange
+	// armored is true, if the shot only wore down armor of the slot without damaging the ship
+	armored bool
+}
+
+// slotChange describes a slot of the board together with its value before the change
+type slotChange struct {
+	pos  Position
+	slot byte
+}
+
+// History returns all of the shots fired so far in order they were fired
+func (g *Game) History() []ShotRecord {
+	history := make([]ShotRecord, len(g.history))
+	for i, r := range g.history {
+		after := g.Stats
+		if i+1 < len(g.history) {
+			after = g.history[i+1].stats
+		}
+		history[i] = ShotRecord{
+			Ordinal:  i + 1,
+			Position: r.pos,
+			Hit:      len(r.ships) > 0 || r.armored,
+			Sunk:     after.SunkShips > r.stats.SunkShips,
+		}
+	}
+	return history
+}
+
+// ShotsByOutcome returns positions of all shots fired so far in order they were fired, split into shots,
+// that hit a ship without sinking it, missed shots and shots, that sunk a ship
+func (g *Game) ShotsByOutcome() (hits, misses, sinks []Position) {
+	for _, r := range g.History() {
+		switch {
+		case r.Sunk:
+			sinks = append(sinks, r.Position)
+		case r.Hit:
+			hits = append(hits, r.Position)
+		default:
+			misses = append(misses, r.Position)
+		}
+	}
+	return hits, misses, sinks
+}
+
+// Frames returns copies of the board with ships visible before the first shot and after every shot in order they were fired.
+// Earlier frames are reconstructed from the history the same way as by UndoN, so the game isn't changed
+func (g *Game) Frames() []*Board {
+	frames := make([]*Board, len(g.history)+1)
+	b := g.board
+	frames[len(g.history)] = g.Board(false)
+	for i := len(g.history) - 1; i >= 0; i-- {
+		r := g.history[i]
+		for j := len(r.changes) - 1; j >= 0; j-- {
+			b.Set(r.changes[j].pos, r.changes[j].slot)
+		}
+		b.Set(r.pos, r.slot)
+		frame := b
+		frames[i] = &frame
+	}
+	return frames
+}
+
+// FleetHistory returns status of every ship in order of placement after every shot in order they were fired.
+// Earlier statuses are reconstructed from the history like in Frames
+func (g *Game) FleetHistory() [][]ShipStatus {
+	history := make([][]ShipStatus, len(g.history))
+	fleet := g.FleetStatus()
+	for i := len(g.history) - 1; i >= 0; i-- {
+		history[i] = append([]ShipStatus(nil), fleet...)
+		for _, idx := range g.history[i].ships {
+			fleet[idx].Health++
+		}
+	}
+	return history
+}
+
+// LuckIndex returns ratio of hits to number of hits expected from random shots. Chance of hitting with every shot
+// is a share of undamaged ship slots among not shot slots before it. Values above 1 mean the shots were luckier
+// than random ones. Returns 0, if no hit was expected
+func (g *Game) LuckIndex() float64 {
+	expected, hits := 0.0, 0
+	frames := g.Frames()
+	for i, r := range g.history {
+		ships, open := 0, 0
+		for _, row := range frames[i] {
+			for _, v := range row {
+				if v == ShipSlot {
+					ships++
+				}
+				if !isShot(v) {
+					open++
+				}
+			}
+		}
+		if open > 0 {
+			expected += float64(ships) / float64(open)
+		}
+		if len(r.ships) > 0 || r.armored {
+			hits++
+		}
+	}
+	if expected == 0 {
+		return 0
+	}
+	return float64(hits) / expected
+}
+
+// RecencyWeights returns, for every shot slot, weight decaying with number of shots fired after it, so the last shot
+// weighs 1 and a shot fired halfLife shots earlier weighs 0.5. Slots not shot have weight 0.
+// Repeated shots are weighted by the latest of them. Half-life lower than 1 is treated as 1
+func (g *Game) RecencyWeights(halfLife int) [Rows][Cols]float64 {
+	halfLife = maxInt(1, halfLife)
+	var weights [Rows][Cols]float64
+	for i, r := range g.history {
+		age := len(g.history) - 1 - i
+		weights[r.pos.row][r.pos.col] = math.Pow(0.5, float64(age)/float64(halfLife))
+	}
+	return weights
+}
+
+// WriteHistoryJSONL writes the game's history to w as JSON lines, one JSON object per shot
+func (g *Game) WriteHistoryJSONL(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	for _, r := range g.History() {
+		if err := enc.Encode(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Undo reverts the last shot. Returns error, if there is no shot to revert
+func (g *Game) Undo() error {
+	return g.UndoN(1)
+}
+
+// UndoN reverts the last n shots restoring the board, statistics and health of damaged ships.
+// Returns error and leaves the game untouched, if there are less than n shots in the history
+func (g *Game) UndoN(n int) error {
+	if n < 0 || n > len(g.history) {
+		return fmt.Errorf("Cannot undo %v shots, there are %v shots in the history", n, len(g.history))
+	}
+	for ; n > 0; n-- {
+		r := g.history[len(g.history)-1]
+		for i := len(r.changes) - 1; i >= 0; i-- {
+			g.board.Set(r.changes[i].pos, r.changes[i].slot)
+		}
+		g.board.Set(r.pos, r.slot)
+		if r.armored {
+			g.armor[r.pos.row][r.pos.col]++
+		}
+		for _, idx := range r.ships {
+			g.ships[idx].health++
+		}
+		hints, skipped, scans := g.Stats.HintsUsed, g.Stats.SkippedTurns, g.Stats.ScansUsed
+		g.Stats = r.stats
+		g.Stats.HintsUsed, g.Stats.SkippedTurns, g.Stats.ScansUsed = hints, skipped, scans
+		g.history = g.history[:len(g.history)-1]
+	}
+	if !g.IsOver() {
+		g.finishedAt = time.Time{}
+	}
+	return nil
+}
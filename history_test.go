@@ -0,0 +1,241 @@
+package battleships
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestUndoN_stateRestored(t *testing.T) {
+	g := newTestGame(
+		testShip{2, Position{0, 0}, Horizontal},
+		testShip{3, Position{5, 5}, Vertical},
+	)
+	g.Shot(Position{9, 9})
+	board := g.board
+	stats := g.Stats
+
+	shots := []Position{{0, 0}, {3, 3}, {0, 1}}
+	for _, s := range shots {
+		g.Shot(s)
+	}
+	if g.Stats.SunkShips != 1 {
+		t.Fatalf("Expected number of sunk ships: %v, got: %v", 1, g.Stats.SunkShips)
+	}
+
+	if err := g.UndoN(len(shots)); err != nil {
+		t.Errorf("Error has been returned %v", err)
+	}
+	if g.board != board {
+		t.Errorf("Expected board: %v, got: %v", board, g.board)
+	}
+	if g.Stats != stats {
+		t.Errorf("Expected statistics: %v, got: %v", stats, g.Stats)
+	}
+	if s := g.ships[0]; s.health != s.size {
+		t.Errorf("Expected ship's health: %v, got: %v", s.size, s.health)
+	}
+	if len(g.history) != 1 {
+		t.Errorf("Expected history length: %v, got: %v", 1, len(g.history))
+	}
+}
+
+func TestUndoN_tooManyShots(t *testing.T) {
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal})
+	g.Shot(Position{0, 0})
+	g.Shot(Position{4, 4})
+	board := g.board
+	stats := g.Stats
+
+	if err := g.UndoN(3); err == nil {
+		t.Error("No error returned when undoing more shots than in the history")
+	}
+	if g.board != board || g.Stats != stats || len(g.history) != 2 {
+		t.Error("Game has been modified by failed undo")
+	}
+}
+
+func TestUndo_emptyHistory(t *testing.T) {
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal})
+
+	if err := g.Undo(); err == nil {
+		t.Error("No error returned when undoing with empty history")
+	}
+}
+
+func TestHistory_shotsRecorded(t *testing.T) {
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal})
+	g.Shot(Position{0, 0})
+	g.Shot(Position{3, 3})
+	g.Shot(Position{0, 1})
+
+	expected := []ShotRecord{
+		{1, Position{0, 0}, true, false},
+		{2, Position{3, 3}, false, false},
+		{3, Position{0, 1}, true, true},
+	}
+	history := g.History()
+	if len(history) != len(expected) {
+		t.Fatalf("Expected history: %v, got: %v", expected, history)
+	}
+	for i, e := range expected {
+		if history[i] != e {
+			t.Errorf("Expected record: %v, got: %v", e, history[i])
+		}
+	}
+}
+
+func TestWriteHistoryJSONL_linePerShot(t *testing.T) {
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal})
+	g.Shot(Position{0, 0})
+	g.Shot(Position{3, 3})
+	g.Shot(Position{0, 1})
+
+	buf := bytes.Buffer{}
+	if err := g.WriteHistoryJSONL(&buf); err != nil {
+		t.Fatalf("Error has been returned %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected number of lines: %v, got: %v", 3, len(lines))
+	}
+	var last ShotRecord
+	if err := json.Unmarshal([]byte(lines[2]), &last); err != nil {
+		t.Fatalf("Line couldn't be decoded: %v", err)
+	}
+	if last != (ShotRecord{3, Position{0, 1}, true, true}) {
+		t.Errorf("Unexpected last record: %v", last)
+	}
+}
+
+func TestShotsByOutcome_historyPartitioned(t *testing.T) {
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal}, testShip{3, Position{5, 5}, Vertical})
+	shots := []Position{{0, 0}, {9, 9}, {5, 5}, {0, 1}, {3, 3}, {6, 5}}
+	for _, p := range shots {
+		g.Shot(p)
+	}
+
+	hits, misses, sinks := g.ShotsByOutcome()
+	data := []struct {
+		name     string
+		got      []Position
+		expected []Position
+	}{
+		{"hits", hits, []Position{{0, 0}, {5, 5}, {6, 5}}},
+		{"misses", misses, []Position{{9, 9}, {3, 3}}},
+		{"sinks", sinks, []Position{{0, 1}}},
+	}
+	for _, d := range data {
+		if !reflect.DeepEqual(d.got, d.expected) {
+			t.Errorf("Expected %v: %v, got: %v", d.name, d.expected, d.got)
+		}
+	}
+	if len(hits)+len(misses)+len(sinks) != len(shots) {
+		t.Errorf("Expected %v shots in total, got: %v", len(shots), len(hits)+len(misses)+len(sinks))
+	}
+}
+
+func TestHistory_armoredHitRecorded(t *testing.T) {
+	g := &Game{}
+	g.PlaceShip(NewArmoredShip(2, 2), Position{0, 0}, Horizontal)
+	g.Ready()
+	g.Shot(Position{0, 0})
+
+	if h := g.History(); len(h) != 1 || !h[0].Hit {
+		t.Errorf("Expected a single hit in the history, got: %v", h)
+	}
+}
+
+func TestRecencyWeights_latestShotWeighsMost(t *testing.T) {
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal})
+	shots := []Position{{5, 5}, {0, 0}, {7, 2}, {3, 3}}
+	for _, p := range shots {
+		g.Shot(p)
+	}
+
+	w := g.RecencyWeights(2)
+	last := shots[len(shots)-1]
+	if w[last.row][last.col] != 1 {
+		t.Errorf("Expected weight of the latest shot: %v, got: %v", 1, w[last.row][last.col])
+	}
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			if p := (Position{uint8(i), uint8(j)}); p != last && w[i][j] >= w[last.row][last.col] {
+				t.Errorf("Slot %v weighs %v, not less than the latest shot", p, w[i][j])
+			}
+		}
+	}
+	if w[0][0] != 0.5 || w[5][5] != math.Pow(0.5, 1.5) || w[9][9] != 0 {
+		t.Errorf("Expected weights: %v, %v and %v, got: %v, %v and %v", 0.5, math.Pow(0.5, 1.5), 0, w[0][0], w[5][5], w[9][9])
+	}
+}
+
+func TestFrames_boardAfterEveryShot(t *testing.T) {
+	g := newTestGameWithOptions(Options{MarkSunkShips: true}, testShip{2, Position{0, 0}, Horizontal}, testShip{3, Position{5, 5}, Vertical})
+	initial := g.Board(false)
+	shots := []Position{{9, 9}, {0, 0}, {0, 1}, {5, 5}}
+	for _, p := range shots {
+		g.Shot(p)
+	}
+
+	frames := g.Frames()
+	if len(frames) != len(shots)+1 {
+		t.Fatalf("Expected number of frames: %v, got: %v", len(shots)+1, len(frames))
+	}
+	if *frames[0] != *initial || *frames[len(shots)] != g.board {
+		t.Error("Expected the first frame before any shot and the last one equal to the current board")
+	}
+	expected := []byte{MissedSlot, HitShipSlot, SunkShipSlot, HitShipSlot}
+	for i, p := range shots {
+		if v := frames[i+1].At(p); v != expected[i] {
+			t.Errorf("Expected slot %v in frame %v: %c, got: %c", p, i+1, expected[i], v)
+		}
+		if v := frames[i].At(p); v != initial.At(p) {
+			t.Errorf("Expected slot %v in frame %v before the shot: %c, got: %c", p, i, initial.At(p), v)
+		}
+	}
+	if frames[2].At(Position{0, 0}) != HitShipSlot {
+		t.Errorf("Expected ship to be marked sunk only after the sinking shot, got: %c", frames[2].At(Position{0, 0}))
+	}
+}
+
+func TestFleetHistory_allShipsSunkAtTheEnd(t *testing.T) {
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal}, testShip{1, Position{5, 5}, Horizontal})
+	shots := []Position{{0, 0}, {9, 9}, {5, 5}, {0, 1}}
+	for _, p := range shots {
+		g.Shot(p)
+	}
+
+	history := g.FleetHistory()
+	if len(history) != len(shots) {
+		t.Fatalf("Expected number of entries: %v, got: %v", len(shots), len(history))
+	}
+	expected := [][]uint8{{1, 1}, {1, 1}, {1, 0}, {0, 0}}
+	for i, fleet := range history {
+		for j, s := range fleet {
+			if s.Health != expected[i][j] {
+				t.Errorf("Expected health of ship %v after shot %v: %v, got: %v", j, i+1, expected[i][j], s.Health)
+			}
+		}
+	}
+	if g.ships[0].health != 0 || g.ships[1].health != 0 {
+		t.Error("Reconstructing fleet history changed the game")
+	}
+}
+
+func TestLuckIndex_perfectGame(t *testing.T) {
+	g := newTestGame(testShip{3, Position{0, 0}, Horizontal}, testShip{2, Position{5, 5}, Vertical})
+	if got := g.LuckIndex(); got != 0 {
+		t.Errorf("Expected luck index without shots: %v, got: %v", 0, got)
+	}
+	for _, p := range g.MinimalSolution() {
+		g.Shot(p)
+	}
+	if got := g.LuckIndex(); got < 10 {
+		t.Errorf("Expected luck index of a perfect game at least: %v, got: %v", 10, got)
+	}
+}
@@ -0,0 +1,89 @@
+package battleships
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+type gameHandler struct {
+	mu   sync.Mutex
+	game *Game
+}
+
+type shotRequest struct {
+	Position Position `json:"position"`
+}
+
+// Handler returns http handler, that allows to play the given game over REST. Supported endpoints are:
+//
+//	GET /board - returns the board with hidden ships
+//	POST /shot - fires a shot at the position given in the body, e.g. {"position":"A5"}, and returns ShotResult
+//	GET /stats - returns the game's statistics
+func Handler(g *Game) http.Handler {
+	h := &gameHandler{game: g}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/board", h.board)
+	mux.HandleFunc("/shot", h.shot)
+	mux.HandleFunc("/stats", h.stats)
+	return mux
+}
+
+func (h *gameHandler) board(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	h.mu.Lock()
+	b := h.game.Board(true)
+	h.mu.Unlock()
+
+	writeJSON(w, b)
+}
+
+func (h *gameHandler) shot(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodPost) {
+		return
+	}
+	var req shotRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.mu.Lock()
+	hit, sunk, err := h.game.Shot(req.Position)
+	h.mu.Unlock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	writeJSON(w, h.game.shotResult(hit, sunk).PublicResult())
+}
+
+func (h *gameHandler) stats(w http.ResponseWriter, r *http.Request) {
+	if !allowMethod(w, r, http.MethodGet) {
+		return
+	}
+	h.mu.Lock()
+	stats := h.game.PublicStats()
+	h.mu.Unlock()
+
+	writeJSON(w, stats)
+}
+
+func allowMethod(w http.ResponseWriter, r *http.Request, method string) bool {
+	if r.Method != method {
+		w.Header().Set("Allow", method)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
@@ -0,0 +1,90 @@
+package battleships
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHandler_boardFetched(t *testing.T) {
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal})
+	g.Shot(Position{0, 0})
+	g.Shot(Position{5, 5})
+
+	rec := httptest.NewRecorder()
+	Handler(g).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/board", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status: %v, got: %v", http.StatusOK, rec.Code)
+	}
+	var b Board
+	if err := json.Unmarshal(rec.Body.Bytes(), &b); err != nil {
+		t.Fatalf("Board couldn't be decoded: %v", err)
+	}
+	if b != *g.Board(true) {
+		t.Errorf("Expected board: %v, got: %v", g.Board(true), b)
+	}
+}
+
+func TestHandler_shotFired(t *testing.T) {
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal}, testShip{3, Position{5, 5}, Horizontal})
+	data := []struct {
+		body   string
+		status int
+		result ShotResult
+	}{
+		{`{"position":"A1"}`, http.StatusOK, ShotResult{Hit: true}},
+		{`{"position":"A2"}`, http.StatusOK, ShotResult{Hit: true, Sunk: true}},
+		{`{"position":"C3"}`, http.StatusOK, ShotResult{}},
+		{`{"position":"C3"}`, http.StatusConflict, ShotResult{}},
+		{`{"position":"Z3"}`, http.StatusBadRequest, ShotResult{}},
+	}
+
+	h := Handler(g)
+	for _, d := range data {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/shot", strings.NewReader(d.body)))
+
+		if rec.Code != d.status {
+			t.Errorf("Expected status: %v, got: %v for body: %v", d.status, rec.Code, d.body)
+			continue
+		}
+		if rec.Code != http.StatusOK {
+			continue
+		}
+		var res ShotResult
+		if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+			t.Errorf("Result couldn't be decoded: %v", err)
+		}
+		if res != d.result {
+			t.Errorf("Expected result: %v, got: %v for body: %v", d.result, res, d.body)
+		}
+	}
+	if g.Stats.ShotsFired != 3 {
+		t.Errorf("Expected number of shots fired: %v, got: %v", 3, g.Stats.ShotsFired)
+	}
+}
+
+func TestHandler_statsAndMethods(t *testing.T) {
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal})
+	g.Shot(Position{5, 5})
+	h := Handler(g)
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/stats", nil))
+	var stats Statistics
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("Statistics couldn't be decoded: %v", err)
+	}
+	if stats != g.Stats {
+		t.Errorf("Expected statistics: %v, got: %v", g.Stats, stats)
+	}
+
+	rec = httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/shot", nil))
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status: %v, got: %v", http.StatusMethodNotAllowed, rec.Code)
+	}
+}
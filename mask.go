@@ -0,0 +1,47 @@
+package battleships
+
+// ShotMask is a bit set with a single bit for every slot of the board, numbered row by row
+type ShotMask [(Rows*Cols + 63) / 64]uint64
+
+// ShotMask returns a bit set, in which bits of all already shot slots are set
+func (g *Game) ShotMask() ShotMask {
+	var m ShotMask
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			if isShot(g.board[i][j]) {
+				m.Set(Position{row: uint8(i), col: uint8(j)})
+			}
+		}
+	}
+	return m
+}
+
+// Set sets the bit of given position
+func (m *ShotMask) Set(p Position) {
+	i := maskIndex(p)
+	m[i/64] |= 1 << (i % 64)
+}
+
+// Has returns true, if the bit of given position is set
+func (m *ShotMask) Has(p Position) bool {
+	i := maskIndex(p)
+	return m[i/64]&(1<<(i%64)) != 0
+}
+
+// Positions returns all positions, which bits are set, ordered row by row
+func (m *ShotMask) Positions() []Position {
+	var positions []Position
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			p := Position{row: uint8(i), col: uint8(j)}
+			if m.Has(p) {
+				positions = append(positions, p)
+			}
+		}
+	}
+	return positions
+}
+
+func maskIndex(p Position) uint {
+	return uint(p.row)*Cols + uint(p.col)
+}
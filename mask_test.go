@@ -0,0 +1,46 @@
+package battleships
+
+import (
+	"testing"
+)
+
+func TestShotMask_positionsRoundTrip(t *testing.T) {
+	positions := []Position{{0, 0}, {0, 9}, {6, 3}, {6, 4}, {9, 9}}
+
+	var m ShotMask
+	for _, p := range positions {
+		m.Set(p)
+	}
+
+	got := m.Positions()
+	if len(got) != len(positions) {
+		t.Fatalf("Expected positions: %v, got: %v", positions, got)
+	}
+	for i, p := range positions {
+		if got[i] != p {
+			t.Errorf("Expected position: %v, got: %v", p, got[i])
+		}
+	}
+	if m.Has(Position{6, 5}) {
+		t.Error("Bit of not set position is set")
+	}
+}
+
+func TestShotMask_shotSlotsSet(t *testing.T) {
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal})
+	shots := []Position{{0, 0}, {7, 7}}
+	for _, s := range shots {
+		g.Shot(s)
+	}
+
+	m := g.ShotMask()
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			p := Position{uint8(i), uint8(j)}
+			expected := p == shots[0] || p == shots[1]
+			if m.Has(p) != expected {
+				t.Errorf("Expected bit of %v: %v, got: %v", p, expected, m.Has(p))
+			}
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package battleships
+
+import (
+	"math/rand"
+	"time"
+)
+
+// AdjacencyMode describes, how close to each other ships can be placed
+type AdjacencyMode int
+
+const (
+	// NoAdjacency forbids ships to touch each other, even diagonally. It is the default mode
+	NoAdjacency AdjacencyMode = iota
+	// AllowAdjacency allows ships to touch each other, as long as they don't overlap
+	AllowAdjacency
+)
+
+// PlacementStrategy describes, how ships are placed randomly on the board
+type PlacementStrategy int
+
+const (
+	// RandomRetries tries random positions, until the ship fits, and falls back to the first legal position.
+	// It is the default strategy
+	RandomRetries PlacementStrategy = iota
+	// UniformPlacement lists all legal placements of the ship and picks one of them at random,
+	// so every placement is equally likely and no retries are needed
+	UniformPlacement
+)
+
+// MinClusterScore defines the lowest ClusterScore of a layout accepted by LayoutQualityOK.
+// Less than 1% of random layouts of StandardFleet score lower
+const MinClusterScore = 4.0
+
+// maxLayoutRerolls limits number of times GenerateGame places the ships again to improve the layout
+const maxLayoutRerolls = 10
+
+// DefaultPlacementRetries defines number of random positions tried for a ship, before all of the positions are scanned
+const DefaultPlacementRetries = 50
+
+// Options defines variant toggles of a game. Zero value describes the default rules
+type Options struct {
+	// Adjacency describes, if ships are allowed to touch each other
+	Adjacency AdjacencyMode `json:"adjacency"`
+	// AllowRepeatShots allows to shoot at already shot positions. Otherwise Shot returns ErrAlreadyShot
+	AllowRepeatShots bool `json:"allowRepeatShots"`
+	// WinThreshold defines number of ships, that need to be sunk to finish the game. Zero means all of the ships
+	WinThreshold int `json:"winThreshold"`
+	// Casual awards NearMissScore points for missed shots next to a ship, including diagonal neighbours
+	Casual bool `json:"casual"`
+	// MarkSunkShips marks all slots of a sunk ship as SunkShipSlot
+	MarkSunkShips bool `json:"markSunkShips"`
+	// AllowOverlap allows ships to share slots. A shot at a shared slot damages all of the ships placed there
+	AllowOverlap bool `json:"allowOverlap"`
+	// PlacementRetries defines number of random positions tried for a ship, before all of the positions are scanned.
+	// Zero means DefaultPlacementRetries
+	PlacementRetries int `json:"placementRetries"`
+	// PlacementStrategy describes, how ships are placed randomly on the board
+	PlacementStrategy PlacementStrategy `json:"placementStrategy,omitempty"`
+	// Wrap treats the board as a torus, so ships can wrap from the last column to the first one and from the last row
+	// to the first one. Slots at opposite edges are neighbours then
+	Wrap bool `json:"wrap,omitempty"`
+	// AmmoLimit limits number of shots, that can be fired. The game is lost, when it is exceeded. Zero means no limit
+	AmmoLimit int `json:"ammoLimit,omitempty"`
+	// RerollPoorLayouts makes GenerateGame place the ships again, if the layout fails LayoutQualityOK
+	RerollPoorLayouts bool `json:"rerollPoorLayouts,omitempty"`
+	// TutorialFirstHit redirects the first shot of the game, if it would miss, to the nearest undamaged ship slot,
+	// so it always hits. The position actually shot is recorded in History
+	TutorialFirstHit bool `json:"tutorialFirstHit,omitempty"`
+	// RevealAroundSunk marks slots around a sunk ship as MissedSlot, as they can't contain ships.
+	// It applies only with NoAdjacency rule, when overlapping isn't allowed
+	RevealAroundSunk bool `json:"revealAroundSunk,omitempty"`
+	// RevealDiagonalsOnHit marks diagonal neighbours of a hit slot as MissedSlot, as they can't contain ships.
+	// It applies only with NoAdjacency rule, when overlapping isn't allowed
+	RevealDiagonalsOnHit bool `json:"revealDiagonalsOnHit,omitempty"`
+	// SilentSinks hides sinking ships from the player, so PublicResult reports only hits and misses
+	// and sunk ships are shown as hit ones on the board with hidden ships. Slots around sunk ships aren't revealed.
+	// Sinks are still counted in statistics
+	SilentSinks bool `json:"silentSinks,omitempty"`
+}
+
+// NewGameWithOptions creates a new game, that follows rules described by given options.
+// The game still needs to be filled with ships before it can be played
+func NewGameWithOptions(opts Options) *Game {
+	return &Game{opts: opts}
+}
+
+// GenerateGame creates a new game with given options filled randomly with the ships, so it is ready to be played.
+// If the options require it, poor layouts are replaced by new ones, which is tried a limited number of times
+func GenerateGame(opts Options, ships []Ship) (*Game, error) {
+	g := NewGameWithOptions(opts)
+	g.rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	for rerolls := 0; ; rerolls++ {
+		if err := g.Reuse(ships); err != nil {
+			return nil, err
+		}
+		if !opts.RerollPoorLayouts || rerolls == maxLayoutRerolls || g.LayoutQualityOK() {
+			return g, nil
+		}
+	}
+}
@@ -0,0 +1,504 @@
+package battleships
+
+import (
+	crand "crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ErrGameStarted is returned, when the game's setup is changed after it has already started
+var ErrGameStarted = errors.New("Game already started")
+
+// ErrShipAlreadyPlaced is returned, when the same ship is placed on the board more than once
+var ErrShipAlreadyPlaced = errors.New("Ship already placed")
+
+// Placement describes a ship together with a position and a direction, at which it is put on the board
+type Placement struct {
+	Ship      Ship
+	Position  Position
+	Direction Direction
+}
+
+// PlaceShip puts a ship manually on the board at given position and direction.
+// After all of the ships are placed, Ready needs to be called to start the game.
+// Returns error, if the ship doesn't fit there, if it was already placed or if the game has already started
+func (g *Game) PlaceShip(s Ship, pos Position, d Direction) error {
+	if g.initialized {
+		return ErrGameStarted
+	}
+	if d != Horizontal && d != Vertical {
+		return fmt.Errorf("Invalid direction %v", d)
+	}
+	if s.size == 0 {
+		return errors.New("Ship of size 0 cannot be placed")
+	}
+	if len(g.ships) == 0 {
+		g.clear()
+	}
+	if g.isPlaced(s.id) {
+		return ErrShipAlreadyPlaced
+	}
+	if !canPlaceShip(g, s, pos, d) {
+		return fmt.Errorf("Ship of size %v cannot be placed at %v", s.size, pos)
+	}
+	placeShip(g, s, pos, d)
+	return nil
+}
+
+// FillRemaining fills the board with fixed ships placed exactly as described and then places randomly the rest of the ships
+// around them. After that, the game is fully initialized and ready to be played.
+// Returns error, if any of the ships couldn't be placed. The game stays not initialized in such case
+func (g *Game) FillRemaining(fixed []Placement, random []Ship) error {
+	rand := rand.New(rand.NewSource(time.Now().Unix()))
+	ships := make([]Ship, 0, len(fixed)+len(random))
+	for _, p := range fixed {
+		ships = append(ships, p.Ship)
+	}
+	ships = append(ships, random...)
+
+	next := 0
+	return g.fill(ships, func(s Ship) bool {
+		next++
+		if next <= len(fixed) {
+			return place(g, fixed[next-1])
+		}
+		return placeRandomly(g, rand, s)
+	})
+}
+
+// place puts a ship on the board as described by the placement. Returns false, if it doesn't fit there
+func place(g *Game, p Placement) bool {
+	if len(p.Ship.shape) > 0 {
+		if !canPlaceShaped(g, p.Ship.shape, p.Position) {
+			return false
+		}
+		placeShaped(g, p.Ship, p.Position)
+		return true
+	}
+	if p.Ship.size == 0 || (p.Direction != Horizontal && p.Direction != Vertical) {
+		return false
+	}
+	if !canPlaceShip(g, p.Ship, p.Position, p.Direction) {
+		return false
+	}
+	placeShip(g, p.Ship, p.Position, p.Direction)
+	return true
+}
+
+// ExportLayout returns placements of all ships on the board in order of placement. Exported ships are undamaged,
+// so the layout can be used to set up a new game with ImportLayout
+func (g *Game) ExportLayout() []Placement {
+	var layout []Placement
+	for _, s := range g.ships {
+		if len(s.shape) > 0 {
+			shaped := NewShapedShip(s.shape...).ship()
+			shaped.armor = s.armor
+			origin := Position{row: s.cells[0].row - s.shape[0].row, col: s.cells[0].col - s.shape[0].col}
+			layout = append(layout, Placement{Ship: shaped, Position: origin, Direction: NoDirection})
+			continue
+		}
+		p := Placement{Ship: NewArmoredShip(s.size, s.armor), Position: s.cells[0], Direction: s.direction}
+		for _, c := range s.cells {
+			// ships wrapping around the edges start at their first cell
+			if !g.opts.Wrap && (c.row < p.Position.row || c.col < p.Position.col) {
+				p.Position = c
+			}
+		}
+		if p.Direction == NoDirection {
+			p.Direction = Horizontal
+		}
+		layout = append(layout, p)
+	}
+	return layout
+}
+
+// ImportLayout fills the board with ships placed exactly as described by the layout, e.g. one returned by ExportLayout.
+// After that, the game is fully initialized and ready to be played
+func (g *Game) ImportLayout(layout []Placement) error {
+	return g.FillRemaining(layout, nil)
+}
+
+// MirrorLayout fills the board with ships of another game flipped horizontally, so both players get mirror-image layouts.
+// After that, the game is fully initialized and ready to be played
+func (g *Game) MirrorLayout(from *Game) error {
+	layout := from.ExportLayout()
+	for i, p := range layout {
+		layout[i] = mirrorPlacement(p)
+	}
+	return g.ImportLayout(layout)
+}
+
+// CommitLayout returns checksum of the ship layout, which a player can publish up front and reveal the layout at the end
+// of the game together with the salt, so it can be checked with VerifyCommit. The salt is random, so the layout
+// can't be guessed by checking checksums of all possible layouts. Shots don't affect the checksum.
+// Returns error, if the salt couldn't be drawn
+func (g *Game) CommitLayout() (commit, salt string, err error) {
+	b := make([]byte, 16)
+	if _, err := crand.Read(b); err != nil {
+		return "", "", fmt.Errorf("Couldn't draw the salt: %v", err)
+	}
+	salt = hex.EncodeToString(b)
+	return layoutChecksum(g.ExportLayout(), salt), salt, nil
+}
+
+// LayoutFingerprint returns FNV-1a hash of the set of slots occupied by ships, so games with ships covering the same slots
+// share the fingerprint regardless of shots fired in them
+func (g *Game) LayoutFingerprint() uint64 {
+	h := fnv.New64a()
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			if isShipSlot(g.board[i][j]) {
+				h.Write([]byte{uint8(i), uint8(j)})
+			}
+		}
+	}
+	return h.Sum64()
+}
+
+// VerifyCommit returns true, if the layout and the salt match the checksum returned by CommitLayout.
+// The order of placements doesn't matter
+func VerifyCommit(layout []Placement, salt, commit string) bool {
+	return layoutChecksum(layout, salt) == commit
+}
+
+// VerifyReplay replays the shots in a new game set up with the layout and checks, that number of fired shots, hits
+// and sunk ships match the claimed statistics. Returns error, if the layout or any of the shots is illegal
+// or if the statistics don't match
+func VerifyReplay(layout []Placement, shots []Position, claimedStats Statistics) error {
+	g := &Game{}
+	if err := g.ImportLayout(layout); err != nil {
+		return fmt.Errorf("Invalid layout: %v", err)
+	}
+	for i, p := range shots {
+		if _, _, err := g.Shot(p); err != nil {
+			return fmt.Errorf("Shot %v at %v is illegal: %v", i+1, p, err)
+		}
+	}
+	got := g.Stats
+	if got.ShotsFired != claimedStats.ShotsFired || got.Hits != claimedStats.Hits || got.SunkShips != claimedStats.SunkShips {
+		return fmt.Errorf("Claimed %v shots, %v hits and %v sunk ships, replay has %v shots, %v hits and %v sunk ships",
+			claimedStats.ShotsFired, claimedStats.Hits, claimedStats.SunkShips, got.ShotsFired, got.Hits, got.SunkShips)
+	}
+	return nil
+}
+
+// layoutChecksum returns hex encoded SHA-256 sum of the salt and the placements sorted,
+// so the checksum doesn't depend on their order
+func layoutChecksum(layout []Placement, salt string) string {
+	lines := make([]string, len(layout))
+	for i, p := range layout {
+		direction := p.Direction
+		if p.Ship.size == 1 {
+			direction = Horizontal
+		}
+		lines[i] = fmt.Sprintf("%v %v %v %v %v", p.Position, direction, p.Ship.size, p.Ship.armor, p.Ship.shape)
+	}
+	sort.Strings(lines)
+	sum := sha256.Sum256([]byte(salt + "\n" + strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// mirrorPlacement flips the placement horizontally, so the first column becomes the last one.
+// Ships wrapping around the edges start at the mirror image of their last column
+func mirrorPlacement(p Placement) Placement {
+	width := uint8(1)
+	if p.Direction == Horizontal {
+		width = p.Ship.size
+	}
+	if len(p.Ship.shape) > 0 {
+		width = shapeWidth(p.Ship.shape)
+		offsets := make([]Position, len(p.Ship.shape))
+		for i, o := range p.Ship.shape {
+			offsets[i] = Position{row: o.row, col: width - 1 - o.col}
+		}
+		p.Ship.shape = offsets
+	}
+	p.Position.col = uint8((2*Cols - int(p.Position.col) - int(width)) % Cols)
+	return p
+}
+
+// ShapedShip describes a ship of any shape, e.g. an L-shaped one, defined by offsets of its slots
+type ShapedShip struct {
+	offsets []Position
+	health  uint8
+	// id identifies the ship the same way as Ship.id, so it's kept by every ship made of it
+	id uint64
+}
+
+// NewShapedShip creates a new ship occupying slots at given offsets relative to its position, e.g. offsets A1, B1, B2
+// describe an L-shaped ship. Offsets are moved, so the topmost and the leftmost of them are in row A and column 1
+func NewShapedShip(offsets ...Position) ShapedShip {
+	s := ShapedShip{
+		offsets: make([]Position, len(offsets)),
+		health:  uint8(len(offsets)),
+		id:      atomic.AddUint64(&lastShipID, 1),
+	}
+	var top, left uint8 = Rows, Cols
+	for _, o := range offsets {
+		if o.row < top {
+			top = o.row
+		}
+		if o.col < left {
+			left = o.col
+		}
+	}
+	for i, o := range offsets {
+		s.offsets[i] = Position{row: o.row - top, col: o.col - left}
+	}
+	return s
+}
+
+func (s ShapedShip) ship() Ship {
+	return Ship{
+		size:      uint8(len(s.offsets)),
+		health:    s.health,
+		direction: NoDirection,
+		shape:     s.offsets,
+		id:        s.id,
+	}
+}
+
+// PlaceShaped puts a shaped ship manually on the board, so its offsets are relative to given position.
+// After all of the ships are placed, Ready needs to be called to start the game.
+// Returns error, if the ship has no slots, if any of its slots is repeated or doesn't fit on the board,
+// if it was already placed or if the game has already started
+func (g *Game) PlaceShaped(s ShapedShip, pos Position) error {
+	if g.initialized {
+		return ErrGameStarted
+	}
+	if len(s.offsets) == 0 {
+		return errors.New("Ship without slots cannot be placed")
+	}
+	if len(g.ships) == 0 {
+		g.clear()
+	}
+	if g.isPlaced(s.id) {
+		return ErrShipAlreadyPlaced
+	}
+	if !canPlaceShaped(g, s.offsets, pos) {
+		return fmt.Errorf("Shaped ship of size %v cannot be placed at %v", len(s.offsets), pos)
+	}
+	placeShaped(g, s.ship(), pos)
+	return nil
+}
+
+// isPlaced returns true, if a ship with given identity is already on the board
+func (g *Game) isPlaced(id uint64) bool {
+	for _, placed := range g.ships {
+		if id != 0 && placed.id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// canPlaceShaped returns true, if none of the offsets is repeated and all of the ship's slots are valid positions
+func canPlaceShaped(g *Game, offsets []Position, pos Position) bool {
+	seen := make(map[Position]bool)
+	for _, o := range offsets {
+		if seen[o] || !isValidPosition(g, pos.row+o.row, pos.col+o.col) {
+			return false
+		}
+		seen[o] = true
+	}
+	return true
+}
+
+func placeShaped(g *Game, ship Ship, pos Position) {
+	ship.cells = nil
+	g.ships = append(g.ships, ship)
+	idx := len(g.ships) - 1
+	for _, o := range ship.shape {
+		g.addShip(idx, Position{row: pos.row + o.row, col: pos.col + o.col})
+	}
+}
+
+// shapeWidth returns number of columns covered by the offsets
+func shapeWidth(offsets []Position) uint8 {
+	width := uint8(0)
+	for _, o := range offsets {
+		if o.col+1 > width {
+			width = o.col + 1
+		}
+	}
+	return width
+}
+
+// Ready finishes manual placement of ships and starts the game.
+// Returns error, if no ships were placed or if the game has already started
+func (g *Game) Ready() error {
+	if g.initialized {
+		return ErrGameStarted
+	}
+	if len(g.ships) == 0 {
+		return errors.New("No ships placed")
+	}
+	g.Stats.InitialShips = len(g.ships)
+	g.start()
+	return nil
+}
+
+// InitFromBoard initializes the game with a board drawn by hand, e.g. read by LoadBoardFile.
+// Ships are inferred from horizontally or vertically connected ship slots, that need to form straight lines.
+// Hit ship slots count as damaged parts of ships and shot slots are counted as already fired shots.
+// Returns error, if the ships don't follow the game's rules or if the game has already started
+func (g *Game) InitFromBoard(b *Board) error {
+	if g.initialized {
+		return ErrGameStarted
+	}
+	g.clear()
+	g.Stats = Statistics{}
+
+	var seen [Rows][Cols]bool
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			v := b[i][j]
+			if slotState(v) == UnknownState {
+				return fmt.Errorf("Unknown slot %q at %v", v, Position{row: uint8(i), col: uint8(j)})
+			}
+			if isShot(v) {
+				g.Stats.ShotsFired++
+			}
+			if isShot(v) && isShipSlot(v) {
+				g.Stats.Hits++
+			}
+			if !isShipSlot(v) || seen[i][j] {
+				continue
+			}
+
+			cells := connectedShipCells(b, Position{row: uint8(i), col: uint8(j)}, &seen)
+			direction, ok := lineDirection(cells)
+			if !ok {
+				return fmt.Errorf("Ship starting at %v is not a straight line", cells[0])
+			}
+			ship := NewShip(uint8(len(cells)))
+			for _, c := range cells {
+				if b.At(c) != ShipSlot {
+					ship.health--
+				}
+			}
+			if ship.health == 0 {
+				g.Stats.SunkShips++
+			}
+			placeShip(g, ship, cells[0], direction)
+		}
+	}
+	if g.opts.Adjacency == NoAdjacency && !g.opts.AllowOverlap && violatesAdjacency(g) {
+		return errors.New("Ships are placed next to each other")
+	}
+
+	g.board = *b
+	g.Stats.InitialShips = len(g.ships)
+	g.start()
+	return nil
+}
+
+// connectedShipCells returns all ship slots connected horizontally or vertically with the start, ordered row by row
+func connectedShipCells(b *Board, start Position, seen *[Rows][Cols]bool) []Position {
+	var cells []Position
+	queue := []Position{start}
+	seen[start.row][start.col] = true
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		cells = append(cells, p)
+		for _, n := range orthogonalNeighbours(p) {
+			if isShipSlot(b.At(n)) && !seen[n.row][n.col] {
+				seen[n.row][n.col] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	sort.Slice(cells, func(i, j int) bool {
+		return cells[i].row < cells[j].row || (cells[i].row == cells[j].row && cells[i].col < cells[j].col)
+	})
+	return cells
+}
+
+// lineDirection returns direction of a straight line formed by cells ordered row by row.
+// Returns false, if the cells don't form such line
+func lineDirection(cells []Position) (Direction, bool) {
+	direction := Horizontal
+	if len(cells) > 1 && cells[1].col == cells[0].col {
+		direction = Vertical
+	}
+	for i, c := range cells {
+		expected := Position{row: cells[0].row, col: cells[0].col + uint8(i)}
+		if direction == Vertical {
+			expected = Position{row: cells[0].row + uint8(i), col: cells[0].col}
+		}
+		if c != expected {
+			return direction, false
+		}
+	}
+	return direction, true
+}
+
+// HasOverlaps returns true, if any slot of the board is occupied by more than one ship.
+// It allows to validate results of custom placers
+func (g *Game) HasOverlaps() bool {
+	var occupied [Rows][Cols]bool
+	for _, s := range g.ships {
+		for _, c := range s.cells {
+			if occupied[c.row][c.col] {
+				return true
+			}
+			occupied[c.row][c.col] = true
+		}
+	}
+	return false
+}
+
+// ViolatesAdjacency returns true, if any two ships touch each other, including diagonal neighbours,
+// although the game's options don't allow it. It allows to validate results of custom placers
+func (g *Game) ViolatesAdjacency() bool {
+	return g.opts.Adjacency == NoAdjacency && violatesAdjacency(g)
+}
+
+// violatesAdjacency returns true, if any two ships touch each other, including diagonal neighbours
+func violatesAdjacency(g *Game) bool {
+	for idx, s := range g.ships {
+		for _, c := range s.cells {
+			touches := anyInNeighbourhood(g, c.row, c.col, func(i, j int) bool {
+				other := g.shipIndex[i][j]
+				return other >= 0 && other != idx
+			})
+			if touches {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// PendingFleet returns ships from the expected fleet, that were not placed on the board yet.
+// Ships are matched by their size
+func (g *Game) PendingFleet(expected []Ship) []Ship {
+	placed := make(map[uint8]int)
+	for _, s := range g.ships {
+		placed[s.size]++
+	}
+
+	var pending []Ship
+	for _, s := range expected {
+		if placed[s.size] > 0 {
+			placed[s.size]--
+		} else {
+			pending = append(pending, s)
+		}
+	}
+	return pending
+}
+
+// FleetComplete returns true, if exactly the expected fleet is placed on the board
+func (g *Game) FleetComplete(expected []Ship) bool {
+	return len(g.ships) == len(expected) && len(g.PendingFleet(expected)) == 0
+}
@@ -0,0 +1,453 @@
+package battleships
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlaceShip_manualSetup(t *testing.T) {
+	g := Game{}
+
+	if err := g.PlaceShip(NewShip(5), Position{0, 0}, Horizontal); err != nil {
+		t.Errorf("Error has been returned %v", err)
+	}
+	if err := g.PlaceShip(NewShip(4), Position{1, 0}, Horizontal); err == nil {
+		t.Error("No error returned for a ship placed next to another one")
+	}
+	if err := g.PlaceShip(NewShip(4), Position{7, 9}, Vertical); err == nil {
+		t.Error("No error returned for a ship placed outside of the board")
+	}
+	if err := g.PlaceShip(NewShip(4), Position{2, 9}, Vertical); err != nil {
+		t.Errorf("Error has been returned %v", err)
+	}
+	if g.Playable() {
+		t.Error("Game is playable before Ready")
+	}
+
+	if err := g.Ready(); err != nil {
+		t.Errorf("Error has been returned %v", err)
+	}
+	if !g.Playable() || g.Stats.InitialShips != 2 {
+		t.Errorf("Game not started properly, stats: %v", g.Stats)
+	}
+	if err := g.PlaceShip(NewShip(2), Position{9, 0}, Horizontal); err != ErrGameStarted {
+		t.Errorf("Expected error: %v, got: %v", ErrGameStarted, err)
+	}
+}
+
+func TestFillRemaining_randomShipsAvoidFixed(t *testing.T) {
+	g := Game{}
+	fixed := []Placement{{NewShip(5), Position{4, 2}, Horizontal}}
+	random := []Ship{NewShip(4), NewShip(4), NewShip(3), NewShip(2)}
+
+	if err := g.FillRemaining(fixed, random); err != nil {
+		t.Fatalf("Error has been returned %v", err)
+	}
+	if !g.Playable() || g.PlacedShipCount() != 5 {
+		t.Errorf("Expected playable game with %v ships, got: %v", 5, g.PlacedShipCount())
+	}
+	for j := uint8(2); j < 7; j++ {
+		if g.shipIndex[4][j] != 0 {
+			t.Errorf("Fixed ship not found at %v", Position{4, j})
+		}
+	}
+	for i := 3; i <= 5; i++ {
+		for j := 1; j <= 7; j++ {
+			if idx := g.shipIndex[i][j]; idx > 0 {
+				t.Errorf("Random ship %v placed next to the fixed one at %v", idx, Position{uint8(i), uint8(j)})
+			}
+		}
+	}
+}
+
+func TestFillRemaining_invalidFixedPlacement(t *testing.T) {
+	g := Game{}
+	fixed := []Placement{
+		{NewShip(5), Position{0, 0}, Horizontal},
+		{NewShip(4), Position{1, 0}, Horizontal},
+	}
+
+	if err := g.FillRemaining(fixed, []Ship{NewShip(2)}); err == nil {
+		t.Error("No error returned for fixed ships next to each other")
+	}
+	if g.initialized {
+		t.Error("Game has been initialized")
+	}
+}
+
+func TestInitFromBoard_shipsInferred(t *testing.T) {
+	b, err := LoadBoardFile("testdata/layout.txt")
+	if err != nil {
+		t.Fatalf("Error has been returned %v", err)
+	}
+	g := Game{}
+	if err := g.InitFromBoard(b); err != nil {
+		t.Fatalf("Error has been returned %v", err)
+	}
+
+	expected := []ShipStatus{
+		{Size: 5, Health: 4, Orientation: Horizontal},
+		{Size: 3, Health: 2, Orientation: Vertical},
+		{Size: 1, Health: 1, Orientation: NoDirection},
+	}
+	fleet := g.FleetStatus()
+	if len(fleet) != len(expected) {
+		t.Fatalf("Expected ships: %v, got: %v", expected, fleet)
+	}
+	for i, e := range expected {
+		if fleet[i] != e {
+			t.Errorf("Expected ship: %v, got: %v", e, fleet[i])
+		}
+	}
+	if g.Stats.ShotsFired != 4 || g.Stats.InitialShips != 3 || !g.Playable() {
+		t.Errorf("Unexpected state of the game, stats: %v", g.Stats)
+	}
+	if hit, sunk, _ := g.Shot(Position{6, 0}); !hit || !sunk {
+		t.Errorf("Expected inferred ship to sink, got hit: %v, sunk: %v", hit, sunk)
+	}
+}
+
+func TestInitFromBoard_invalidShips(t *testing.T) {
+	data := [][]string{
+		{"SS", "-S"},
+		{"S-", "-S"},
+		{"S?"},
+	}
+
+	for _, d := range data {
+		b := Board{}
+		for i := range b {
+			for j := range b[i] {
+				b[i][j] = EmptySlot
+			}
+		}
+		for i, row := range d {
+			copy(b[i][:], row)
+		}
+
+		g := Game{}
+		if err := g.InitFromBoard(&b); err == nil {
+			t.Errorf("No error returned for board: %v", d)
+		}
+	}
+}
+
+func TestLoadBoardFile_invalidFiles(t *testing.T) {
+	if _, err := LoadBoardFile("testdata/missing.txt"); err == nil {
+		t.Error("No error returned for a missing file")
+	}
+	data := []string{
+		"SSSS\n",
+		strings.Repeat("----------\n", Rows+1),
+		strings.Repeat("---------?\n", Rows),
+	}
+	for _, d := range data {
+		if _, err := readBoard(strings.NewReader(d)); err == nil {
+			t.Errorf("No error returned for input: %q", d)
+		}
+	}
+}
+
+func TestReady_noShips(t *testing.T) {
+	g := Game{}
+
+	if err := g.Ready(); err == nil {
+		t.Error("No error returned for a game without ships")
+	}
+}
+
+func TestReady_afterFailedFill(t *testing.T) {
+	g := Game{}
+	ships := []Ship{NewShip(10), NewShip(10), NewShip(10), NewShip(10), NewShip(10), NewShip(10)}
+	if err := g.FillBoard(ships); err == nil {
+		t.Fatal("Expected error for a fleet not fitting on the board")
+	}
+	if g.PlacedShipCount() != 0 {
+		t.Errorf("Expected no ships left on the board, got: %v", g.PlacedShipCount())
+	}
+	if err := g.Ready(); err == nil {
+		t.Error("Expected error starting the game after a failed fill")
+	}
+}
+
+func TestFleetComplete_partialFleet(t *testing.T) {
+	g := Game{}
+	fleet := []Ship{NewShip(5), NewShip(4), NewShip(4)}
+
+	g.PlaceShip(NewShip(4), Position{0, 0}, Horizontal)
+	if g.FleetComplete(fleet) {
+		t.Error("Partial fleet reported as complete")
+	}
+	pending := g.PendingFleet(fleet)
+	if len(pending) != 2 || pending[0].size != 5 || pending[1].size != 4 {
+		t.Errorf("Expected pending ships of sizes 5 and 4, got: %v", pending)
+	}
+
+	g.PlaceShip(NewShip(4), Position{2, 0}, Horizontal)
+	g.PlaceShip(NewShip(5), Position{4, 0}, Horizontal)
+	if !g.FleetComplete(fleet) {
+		t.Error("Complete fleet reported as not complete")
+	}
+
+	g.PlaceShip(NewShip(2), Position{6, 0}, Horizontal)
+	if g.FleetComplete(fleet) {
+		t.Error("Fleet with an extra ship reported as complete")
+	}
+}
+
+func TestMirrorLayout_horizontalFlip(t *testing.T) {
+	from := newTestGame(
+		testShip{5, Position{0, 0}, Horizontal},
+		testShip{3, Position{2, 7}, Vertical},
+		testShip{1, Position{9, 4}, Horizontal},
+	)
+	g := &Game{}
+	if err := g.MirrorLayout(from); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if !g.Playable() || len(g.ships) != len(from.ships) {
+		t.Fatalf("Expected playable game with %v ships, got %v ships", len(from.ships), len(g.ships))
+	}
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			if g.board[i][j] != from.board[i][Cols-1-j] {
+				t.Errorf("Expected slot %v to mirror slot %v", Position{uint8(i), uint8(j)}, Position{uint8(i), uint8(Cols - 1 - j)})
+			}
+		}
+	}
+}
+
+func TestMirrorLayout_wrappedShips(t *testing.T) {
+	from := newTestGameWithOptions(Options{Wrap: true},
+		testShip{4, Position{0, 8}, Horizontal},
+		testShip{3, Position{8, 3}, Vertical},
+	)
+	g := NewGameWithOptions(Options{Wrap: true})
+	if err := g.MirrorLayout(from); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			if g.board[i][j] != from.board[i][Cols-1-j] {
+				t.Errorf("Expected slot %v to mirror slot %v", Position{uint8(i), uint8(j)}, Position{uint8(i), uint8(Cols - 1 - j)})
+			}
+		}
+	}
+}
+
+func TestViolatesAdjacency_acrossWrappedEdge(t *testing.T) {
+	g := newTestGameWithOptions(Options{Wrap: true},
+		testShip{2, Position{0, 0}, Vertical},
+		testShip{2, Position{5, 9}, Vertical},
+		testShip{2, Position{9, 5}, Horizontal},
+	)
+	if g.ViolatesAdjacency() {
+		t.Error("Expected separate ships not to violate adjacency")
+	}
+	g = newTestGameWithOptions(Options{Wrap: true},
+		testShip{2, Position{0, 0}, Vertical},
+		testShip{2, Position{1, 9}, Vertical},
+	)
+	if !g.ViolatesAdjacency() {
+		t.Error("Expected ships touching across the edge to violate adjacency")
+	}
+}
+
+func TestImportLayout_exportedLayoutRestored(t *testing.T) {
+	from := &Game{}
+	from.FillBoard(StandardFleet())
+	from.Shot(Position{0, 0})
+
+	g := &Game{}
+	if err := g.ImportLayout(from.ExportLayout()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if g.shipIndex != from.shipIndex || g.Stats.ShotsFired != 0 {
+		t.Errorf("Expected the same layout without shots, got:\n%v", g.Board(false))
+	}
+}
+
+func TestHasOverlaps(t *testing.T) {
+	overlapping := newTestGame(testShip{3, Position{0, 0}, Horizontal}, testShip{3, Position{0, 2}, Vertical})
+	if !overlapping.HasOverlaps() {
+		t.Error("Expected overlapping ships to be detected")
+	}
+	separate := newTestGame(testShip{3, Position{0, 0}, Horizontal}, testShip{3, Position{0, 3}, Vertical})
+	if separate.HasOverlaps() {
+		t.Error("Expected touching ships not to be reported as overlapping")
+	}
+}
+
+func TestViolatesAdjacency(t *testing.T) {
+	ships := []testShip{{3, Position{0, 0}, Horizontal}, {3, Position{1, 3}, Vertical}}
+	data := []struct {
+		opts     Options
+		expected bool
+	}{
+		{Options{}, true},
+		{Options{Adjacency: AllowAdjacency}, false},
+	}
+
+	for _, d := range data {
+		g := newTestGameWithOptions(d.opts, ships...)
+		if got := g.ViolatesAdjacency(); got != d.expected {
+			t.Errorf("Expected adjacency violation: %v, got: %v for options: %+v", d.expected, got, d.opts)
+		}
+	}
+	if g := newTestGame(ships[0], testShip{3, Position{2, 3}, Vertical}); g.ViolatesAdjacency() {
+		t.Error("Expected separate ships not to violate adjacency")
+	}
+}
+
+func TestVerifyCommit_matchingAndTamperedLayout(t *testing.T) {
+	g := newTestGame(testShip{5, Position{0, 0}, Horizontal}, testShip{3, Position{4, 4}, Vertical})
+	commit, salt, err := g.CommitLayout()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	g.Shot(Position{0, 0})
+
+	layout := g.ExportLayout()
+	if !VerifyCommit(layout, salt, commit) {
+		t.Error("Expected revealed layout to match the commit")
+	}
+	if _, other, _ := g.CommitLayout(); VerifyCommit(layout, other, commit) {
+		t.Error("Expected layout with a different salt not to match the commit")
+	}
+	layout[0], layout[1] = layout[1], layout[0]
+	if !VerifyCommit(layout, salt, commit) {
+		t.Error("Expected reordered layout to match the commit")
+	}
+	layout[0].Position.row++
+	if VerifyCommit(layout, salt, commit) {
+		t.Error("Expected tampered layout not to match the commit")
+	}
+	if VerifyCommit(layout[1:], salt, commit) {
+		t.Error("Expected layout with a missing ship not to match the commit")
+	}
+}
+
+func TestPlaceShip_sameShipTwice(t *testing.T) {
+	g := Game{}
+	s := NewShip(3)
+
+	if err := g.PlaceShip(s, Position{0, 0}, Horizontal); err != nil {
+		t.Errorf("Error has been returned %v", err)
+	}
+	if err := g.PlaceShip(s, Position{5, 5}, Vertical); err != ErrShipAlreadyPlaced {
+		t.Errorf("Expected error: %v, got: %v", ErrShipAlreadyPlaced, err)
+	}
+	if err := g.PlaceShip(NewShip(3), Position{5, 5}, Vertical); err != nil {
+		t.Errorf("Error has been returned for a different ship of the same size %v", err)
+	}
+	if len(g.ships) != 2 {
+		t.Errorf("Expected number of placed ships: %v, got: %v", 2, len(g.ships))
+	}
+}
+
+func TestPlaceShaped_sameShipTwice(t *testing.T) {
+	g := Game{}
+	s := NewShapedShip(Position{0, 0}, Position{1, 0}, Position{1, 1})
+
+	if err := g.PlaceShaped(s, Position{0, 0}); err != nil {
+		t.Errorf("Error has been returned %v", err)
+	}
+	if err := g.PlaceShaped(s, Position{5, 5}); err != ErrShipAlreadyPlaced {
+		t.Errorf("Expected error: %v, got: %v", ErrShipAlreadyPlaced, err)
+	}
+	if err := g.PlaceShaped(NewShapedShip(Position{0, 0}, Position{1, 0}, Position{1, 1}), Position{5, 5}); err != nil {
+		t.Errorf("Error has been returned for a different ship of the same shape %v", err)
+	}
+	if len(g.ships) != 2 {
+		t.Errorf("Expected number of placed ships: %v, got: %v", 2, len(g.ships))
+	}
+}
+
+func TestPlaceShaped_lShapedShipSunk(t *testing.T) {
+	offsets := make([]Position, 0, 3)
+	for _, in := range []string{"A1", "B1", "B2"} {
+		p, _ := ConvertInputToPosition(in)
+		offsets = append(offsets, *p)
+	}
+	g := &Game{}
+	if err := g.PlaceShaped(NewShapedShip(offsets...), Position{4, 4}); err != nil {
+		t.Fatalf("Error has been returned %v", err)
+	}
+	if err := g.PlaceShaped(NewShapedShip(offsets...), Position{9, 0}); err == nil {
+		t.Error("No error returned for a shaped ship placed outside of the board")
+	}
+	if err := g.PlaceShaped(NewShapedShip(Position{0, 0}, Position{0, 0}), Position{0, 0}); err == nil {
+		t.Error("No error returned for a shaped ship with repeated slots")
+	}
+	g.Ready()
+
+	if notation := g.LayoutNotation(); len(notation) != 1 || notation[0] != "Cruiser E5,F5,F6" {
+		t.Errorf("Expected notation of shaped ship: %v, got: %v", "Cruiser E5,F5,F6", notation)
+	}
+	for i, p := range []Position{{4, 4}, {5, 4}, {5, 5}} {
+		hit, sunk, err := g.Shot(p)
+		if !hit || sunk != (i == 2) || err != nil {
+			t.Errorf("Expected hit with sunk: %v, got: hit %v, sunk %v, error %v at %v", i == 2, hit, sunk, err, p)
+		}
+	}
+	if !g.IsOver() {
+		t.Error("Expected game to be over after sinking the shaped ship")
+	}
+}
+
+func TestMirrorLayout_shapedShip(t *testing.T) {
+	from := &Game{}
+	from.PlaceShaped(NewShapedShip(Position{0, 0}, Position{1, 0}, Position{1, 1}), Position{0, 0})
+	from.Ready()
+
+	g := &Game{}
+	if err := g.MirrorLayout(from); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			if g.board[i][j] != from.board[i][Cols-1-j] {
+				t.Errorf("Expected slot %v to mirror slot %v", Position{uint8(i), uint8(j)}, Position{uint8(i), uint8(Cols - 1 - j)})
+			}
+		}
+	}
+}
+
+func TestVerifyReplay_validAndTamperedStats(t *testing.T) {
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal}, testShip{3, Position{4, 4}, Vertical})
+	shots := []Position{{9, 9}, {0, 0}, {0, 1}, {4, 4}}
+	for _, p := range shots {
+		g.Shot(p)
+	}
+	layout := g.ExportLayout()
+
+	if err := VerifyReplay(layout, shots, g.Stats); err != nil {
+		t.Errorf("Expected valid replay to be accepted, got: %v", err)
+	}
+	tampered := g.Stats
+	tampered.Hits++
+	if err := VerifyReplay(layout, shots, tampered); err == nil {
+		t.Error("Expected replay with tampered statistics to be rejected")
+	}
+	if err := VerifyReplay(layout, append(shots, Position{9, 9}), g.Stats); err == nil {
+		t.Error("Expected replay with a repeated shot to be rejected")
+	}
+}
+
+func TestLayoutFingerprint_sameLayoutDifferentPlay(t *testing.T) {
+	ships := []testShip{{3, Position{0, 0}, Horizontal}, {2, Position{5, 5}, Vertical}}
+	g, other := newTestGame(ships...), newTestGame(ships...)
+	g.Shot(Position{0, 0})
+	g.Shot(Position{9, 9})
+	other.Shot(Position{5, 5})
+	other.Shot(Position{6, 5})
+
+	if g.LayoutFingerprint() != other.LayoutFingerprint() {
+		t.Error("Expected games with the same layout to share the fingerprint")
+	}
+	if moved := newTestGame(ships[0], testShip{2, Position{5, 6}, Vertical}); moved.LayoutFingerprint() == g.LayoutFingerprint() {
+		t.Error("Expected different layouts to have different fingerprints")
+	}
+}
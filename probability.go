@@ -0,0 +1,359 @@
+package battleships
+
+import (
+	"errors"
+	"math"
+)
+
+// ProbabilityMap returns, for every slot, likelihood of finding a ship there based only on information visible to the player.
+// Placements of the remaining ships, that don't cross missed shots or sunk ships, are counted for every not shot slot
+// they cover and the counts are normalized, so they sum up to 1. Shot slots have probability 0
+func (g *Game) ProbabilityMap() [Rows][Cols]float64 {
+	m, _ := placementCounts(&g.board, g.RemainingShipSizes())
+	return normalized(&g.board, m)
+}
+
+// BestShot returns the not shot slot with the highest probability in ProbabilityMap.
+// Returns error, if the game is not playable
+func (g *Game) BestShot() (Position, error) {
+	if !g.Playable() {
+		return Position{}, errors.New("Game is not playable")
+	}
+	m := g.ProbabilityMap()
+	best, found := Position{}, false
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			p := Position{row: uint8(i), col: uint8(j)}
+			if isShot(g.board.At(p)) {
+				continue
+			}
+			if !found || m[i][j] > m[best.row][best.col] {
+				best, found = p, true
+			}
+		}
+	}
+	if !found {
+		return Position{}, errors.New("No slots left to shoot at")
+	}
+	return best, nil
+}
+
+// InfoGainShot returns the not shot slot, a shot at which is expected to reduce BeliefEntropy the most.
+// Entropy after a hit and after a miss is weighted by the probability of hitting a ship in the slot.
+// Returns error, if the game is not playable
+func (g *Game) InfoGainShot() (Position, error) {
+	if !g.Playable() {
+		return Position{}, errors.New("Game is not playable")
+	}
+	sizes := g.RemainingShipSizes()
+	var hitChance [Rows][Cols]float64
+	for _, size := range sizes {
+		counts, totals := placementCounts(&g.board, []uint8{size})
+		if totals[0] == 0 {
+			continue
+		}
+		for i := 0; i < Rows; i++ {
+			for j := 0; j < Cols; j++ {
+				hitChance[i][j] = math.Min(1, hitChance[i][j]+counts[i][j]/totals[0])
+			}
+		}
+	}
+	entropyAfter := func(p Position, slot byte) float64 {
+		b := g.board
+		b.Set(p, slot)
+		counts, _ := placementCounts(&b, sizes)
+		m := normalized(&b, counts)
+		return entropy(&m)
+	}
+
+	best, bestEntropy, found := Position{}, 0.0, false
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			p := Position{row: uint8(i), col: uint8(j)}
+			if isShot(g.board.At(p)) {
+				continue
+			}
+			chance := hitChance[i][j]
+			expected := chance*entropyAfter(p, HitShipSlot) + (1-chance)*entropyAfter(p, MissedSlot)
+			if !found || expected < bestEntropy {
+				best, bestEntropy, found = p, expected, true
+			}
+		}
+	}
+	if !found {
+		return Position{}, errors.New("No slots left to shoot at")
+	}
+	return best, nil
+}
+
+// ForcedHit returns a not shot slot, that has to contain a ship, because every placement of one of the remaining ships
+// consistent with the visible board covers it. Returns false, if there is no such slot or if the ships' placements
+// can't be enumerated, because they wrap around the edges or are shaped
+func (g *Game) ForcedHit() (Position, bool) {
+	if !g.Playable() || !g.straightPlacements() {
+		return Position{}, false
+	}
+	for _, size := range g.RemainingShipSizes() {
+		counts, totals := placementCounts(&g.board, []uint8{size})
+		if totals[0] == 0 {
+			continue
+		}
+		for i := 0; i < Rows; i++ {
+			for j := 0; j < Cols; j++ {
+				if counts[i][j] == totals[0] && !isShot(g.board[i][j]) {
+					return Position{row: uint8(i), col: uint8(j)}, true
+				}
+			}
+		}
+	}
+	return Position{}, false
+}
+
+// ForcedWater returns not shot positions, that can't contain a ship, because no placement of the remaining ships
+// consistent with the visible board covers them. Positions are ordered row by row.
+// Returns nil, if the ships' placements can't be enumerated, because they wrap around the edges or are shaped
+func (g *Game) ForcedWater() []Position {
+	if !g.Playable() || !g.straightPlacements() {
+		return nil
+	}
+	counts, _ := placementCounts(&g.board, g.RemainingShipSizes())
+	var water []Position
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			if counts[i][j] == 0 && !isShot(g.board[i][j]) {
+				water = append(water, Position{row: uint8(i), col: uint8(j)})
+			}
+		}
+	}
+	return water
+}
+
+// PossibleShipCells returns not shot positions covered by at least one of LegalPlacements of the remaining ships
+// on the visible board. All of the other not shot positions are returned by ForcedWater. Positions are ordered row by row.
+// If the ships' placements can't be enumerated, because they wrap around the edges or are shaped, all not shot
+// positions are returned
+func (g *Game) PossibleShipCells() []Position {
+	if !g.Playable() {
+		return nil
+	}
+	var covered [Rows][Cols]bool
+	seen := make(map[uint8]bool)
+	for _, size := range g.RemainingShipSizes() {
+		if !g.straightPlacements() {
+			for i := range covered {
+				for j := range covered[i] {
+					covered[i][j] = true
+				}
+			}
+			break
+		}
+		if seen[size] {
+			continue
+		}
+		seen[size] = true
+		for _, p := range g.LegalPlacements(size, true) {
+			for n := 0; n < int(size); n++ {
+				if p.Direction == Horizontal {
+					covered[p.Position.row][int(p.Position.col)+n] = true
+				} else {
+					covered[int(p.Position.row)+n][p.Position.col] = true
+				}
+			}
+		}
+	}
+
+	var cells []Position
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			if covered[i][j] && !isShot(g.board[i][j]) {
+				cells = append(cells, Position{row: uint8(i), col: uint8(j)})
+			}
+		}
+	}
+	return cells
+}
+
+// AutoPlayStep shoots at a slot returned by ForcedHit, the most probable slot next to a hit one or the slot returned
+// by BestShot, in that order of preference, and returns the position shot.
+// Slots returned by ForcedWater have probability 0, so they are shot only, if no other slot is left.
+// Returns error, if the game is not playable or the shot fails
+func (g *Game) AutoPlayStep() (Position, error) {
+	pos, ok := g.ForcedHit()
+	if !ok {
+		pos, ok = g.bestTarget()
+	}
+	if !ok {
+		var err error
+		if pos, err = g.BestShot(); err != nil {
+			return Position{}, err
+		}
+	}
+	if _, _, err := g.Shot(pos); err != nil {
+		return Position{}, err
+	}
+	return pos, nil
+}
+
+// bestTarget returns the slot from TargetCandidates with the highest probability in ProbabilityMap.
+// Returns false, if there is no candidate with non-zero probability
+func (g *Game) bestTarget() (Position, bool) {
+	m := g.ProbabilityMap()
+	best, found := Position{}, false
+	for _, p := range g.TargetCandidates() {
+		if m[p.row][p.col] > 0 && (!found || m[p.row][p.col] > m[best.row][best.col]) {
+			best, found = p, true
+		}
+	}
+	return best, found
+}
+
+// BeliefEntropy returns entropy in bits of the distribution returned by ProbabilityMap.
+// It describes uncertainty about positions of the remaining ships and decreases, as shots reveal more of the board
+func (g *Game) BeliefEntropy() float64 {
+	m := g.ProbabilityMap()
+	return entropy(&m)
+}
+
+func entropy(m *[Rows][Cols]float64) float64 {
+	h := 0.0
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			if p := m[i][j]; p > 0 {
+				h -= p * math.Log2(p)
+			}
+		}
+	}
+	return h
+}
+
+// LegalPlacements returns all placements of a ship of given size, ordered row by row and horizontal ones first.
+// For the visible board only missed shots and sunk ships block a placement, like in ProbabilityMap.
+// Otherwise the placement needs to follow the game's rules considering the actual ships
+func (g *Game) LegalPlacements(size uint8, visible bool) []Placement {
+	if visible {
+		return visiblePlacements(&g.board, size)
+	}
+	var placements []Placement
+	forEachPlacement(size, func(pos Position, d Direction) {
+		if canPlaceShip(g, Ship{size: size}, pos, d) {
+			placements = append(placements, Placement{Ship: NewShip(size), Position: pos, Direction: d})
+		}
+	})
+	return placements
+}
+
+// UncertaintyBySize returns, for every size of ships still afloat, number of their placements consistent
+// with the visible board. Low counts mean the ships are nearly pinned down
+func (g *Game) UncertaintyBySize() map[uint8]int {
+	uncertainty := make(map[uint8]int)
+	for _, size := range g.RemainingShipSizes() {
+		if _, ok := uncertainty[size]; !ok {
+			uncertainty[size] = len(g.LegalPlacements(size, true))
+		}
+	}
+	return uncertainty
+}
+
+// straightPlacements returns true, if all of the ships are straight lines not wrapping around the board's edges,
+// so their placements can be enumerated by visiblePlacements
+func (g *Game) straightPlacements() bool {
+	if g.opts.Wrap {
+		return false
+	}
+	for _, s := range g.ships {
+		if len(s.shape) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// visiblePlacements returns placements of a ship of given size consistent with the visible board
+func visiblePlacements(b *Board, size uint8) []Placement {
+	var placements []Placement
+	forEachPlacement(size, func(pos Position, d Direction) {
+		if couldPlaceUnknown(b, size, pos, d) {
+			placements = append(placements, Placement{Ship: Ship{size: size, health: size}, Position: pos, Direction: d})
+		}
+	})
+	return placements
+}
+
+// forEachPlacement calls f for every position and direction, at which a ship with given size could start.
+// Single slot ships are placed only horizontally, so every placement is visited once
+func forEachPlacement(size uint8, f func(pos Position, d Direction)) {
+	if size == 0 {
+		return
+	}
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			for _, d := range []Direction{Horizontal, Vertical} {
+				if size == 1 && d == Vertical {
+					continue
+				}
+				f(Position{row: uint8(i), col: uint8(j)}, d)
+			}
+		}
+	}
+}
+
+// placementCounts returns, for every slot, number of placements of ships of given sizes covering it and the total
+// number of placements of each of the sizes. Only placements consistent with the visible board are counted
+func placementCounts(b *Board, sizes []uint8) ([Rows][Cols]float64, []float64) {
+	var counts [Rows][Cols]float64
+	totals := make([]float64, len(sizes))
+	for k, size := range sizes {
+		for _, p := range visiblePlacements(b, size) {
+			totals[k]++
+			for n := 0; n < int(size); n++ {
+				if p.Direction == Horizontal {
+					counts[p.Position.row][int(p.Position.col)+n]++
+				} else {
+					counts[int(p.Position.row)+n][p.Position.col]++
+				}
+			}
+		}
+	}
+	return counts, totals
+}
+
+// couldPlaceUnknown works the same way as couldPlace, but sunk ships block the placement too
+func couldPlaceUnknown(b *Board, size uint8, pos Position, direction Direction) bool {
+	if !couldPlace(b, size, pos, direction) {
+		return false
+	}
+	for n := uint8(0); n < size; n++ {
+		row, col := pos.row, pos.col
+		if direction == Horizontal {
+			col += n
+		} else {
+			row += n
+		}
+		if b[row][col] == SunkShipSlot {
+			return false
+		}
+	}
+	return true
+}
+
+// normalized returns counts of not shot slots divided by their sum, counts of shot slots are replaced by 0
+func normalized(b *Board, counts [Rows][Cols]float64) [Rows][Cols]float64 {
+	sum := 0.0
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			if isShot(b[i][j]) {
+				counts[i][j] = 0
+			}
+			sum += counts[i][j]
+		}
+	}
+	if sum == 0 {
+		return counts
+	}
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			counts[i][j] /= sum
+		}
+	}
+	return counts
+}
@@ -0,0 +1,230 @@
+package battleships
+
+import (
+	"math"
+	"testing"
+)
+
+func TestProbabilityMap_normalized(t *testing.T) {
+	g := newTestGame(testShip{3, Position{0, 0}, Horizontal}, testShip{2, Position{5, 5}, Vertical})
+	g.Shot(Position{0, 0})
+	g.Shot(Position{4, 4})
+
+	m := g.ProbabilityMap()
+	sum := 0.0
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			sum += m[i][j]
+		}
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("Expected probabilities to sum up to 1, got: %v", sum)
+	}
+	if m[0][0] != 0 || m[4][4] != 0 {
+		t.Errorf("Expected shot slots to have probability 0, got: %v and %v", m[0][0], m[4][4])
+	}
+	if m[9][9] >= m[5][5] {
+		t.Errorf("Expected corner to be less likely than the centre, got: %v and %v", m[9][9], m[5][5])
+	}
+}
+
+func TestBestShot_mostLikelySlot(t *testing.T) {
+	g := newTestGame(testShip{5, Position{0, 0}, Horizontal})
+
+	best, err := g.BestShot()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	m := g.ProbabilityMap()
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			if m[i][j] > m[best.row][best.col] {
+				t.Errorf("Slot %v is more likely than the best shot %v", Position{uint8(i), uint8(j)}, best)
+			}
+		}
+	}
+	if _, err := (&Game{}).BestShot(); err == nil {
+		t.Error("Expected error for not initialized game")
+	}
+}
+
+func TestBeliefEntropy_decreasesAfterShot(t *testing.T) {
+	g := newTestGame(testShip{3, Position{0, 0}, Horizontal})
+
+	before := g.BeliefEntropy()
+	g.Shot(Position{5, 5})
+	if after := g.BeliefEntropy(); after >= before {
+		t.Errorf("Expected entropy to decrease after a miss, got: %v before and %v after", before, after)
+	}
+}
+
+func TestInfoGainShot_differsFromBestShot(t *testing.T) {
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal}, testShip{5, Position{9, 0}, Horizontal})
+	for _, p := range []Position{{1, 1}, {1, 3}, {3, 1}, {3, 3}} {
+		g.Shot(p)
+	}
+
+	best, err := g.BestShot()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	info, err := g.InfoGainShot()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if info == best {
+		t.Errorf("Expected the most informative shot to differ from the best shot %v", best)
+	}
+	if isShot(g.board.At(info)) {
+		t.Errorf("Expected the most informative shot at a not shot slot, got: %v", info)
+	}
+	if _, err := (&Game{}).InfoGainShot(); err == nil {
+		t.Error("Expected error for not initialized game")
+	}
+}
+
+func TestForcedHit_constrainedBoard(t *testing.T) {
+	g := newTestGame(testShip{5, Position{0, 2}, Horizontal})
+	if _, ok := g.ForcedHit(); ok {
+		t.Error("Expected no forced hit on an empty board")
+	}
+
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			if i > 0 || j == Cols-1 {
+				g.Shot(Position{uint8(i), uint8(j)})
+			}
+		}
+	}
+	pos, ok := g.ForcedHit()
+	if !ok || pos != (Position{0, 4}) {
+		t.Errorf("Expected forced hit at: %v, got: %v, %v", Position{0, 4}, pos, ok)
+	}
+}
+
+func TestForcedWater_cornerCutOffByMisses(t *testing.T) {
+	g := newTestGame(testShip{3, Position{5, 5}, Vertical})
+	if water := g.ForcedWater(); len(water) != 0 {
+		t.Errorf("Expected no forced water on an empty board, got: %v", water)
+	}
+
+	g.Shot(Position{0, 1})
+	g.Shot(Position{1, 0})
+	water := g.ForcedWater()
+	if len(water) != 1 || water[0] != (Position{0, 0}) {
+		t.Errorf("Expected forced water: %v, got: %v", []Position{{0, 0}}, water)
+	}
+}
+
+func TestForcedWater_wrappedShip(t *testing.T) {
+	g := newTestGameWithOptions(Options{Wrap: true}, testShip{3, Position{0, 8}, Horizontal})
+	for _, p := range []Position{{0, 1}, {1, 0}, {9, 0}} {
+		g.Shot(p)
+	}
+
+	for _, p := range g.ForcedWater() {
+		if g.shipIndex[p.row][p.col] >= 0 {
+			t.Errorf("Expected slot %v of the wrapped ship not to be forced water", p)
+		}
+	}
+	for _, p := range []Position{{0, 0}, {0, 8}} {
+		if !containsPosition(g.PossibleShipCells(), p) {
+			t.Errorf("Expected slot %v of the wrapped ship to be a possible ship cell", p)
+		}
+	}
+	if p, ok := g.ForcedHit(); ok && g.shipIndex[p.row][p.col] < 0 {
+		t.Errorf("Expected no forced hit outside of the ship, got: %v", p)
+	}
+}
+
+func containsPosition(positions []Position, p Position) bool {
+	for _, q := range positions {
+		if q == p {
+			return true
+		}
+	}
+	return false
+}
+
+func TestPossibleShipCells_safeCellsExcluded(t *testing.T) {
+	g := newTestGame(testShip{3, Position{5, 5}, Vertical}, testShip{3, Position{8, 0}, Horizontal})
+	g.Shot(Position{0, 1})
+	g.Shot(Position{1, 0})
+	g.Shot(Position{2, 2})
+
+	cells := g.PossibleShipCells()
+	if len(cells)+len(g.ForcedWater()) != Rows*Cols-3 {
+		t.Errorf("Expected possible ship cells and forced water to cover all not shot slots, got: %v", cells)
+	}
+	for _, c := range cells {
+		if c == (Position{0, 0}) || c == (Position{2, 2}) {
+			t.Errorf("Expected provably safe or shot cell %v to be excluded", c)
+		}
+	}
+	if cells[0] != (Position{0, 2}) {
+		t.Errorf("Expected first possible ship cell: %v, got: %v", Position{0, 2}, cells[0])
+	}
+}
+
+func TestAutoPlayStep_playedToCompletion(t *testing.T) {
+	g := newTestGame(testShip{3, Position{2, 3}, Horizontal}, testShip{2, Position{7, 6}, Vertical})
+	for g.Playable() {
+		if _, err := g.AutoPlayStep(); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	if !g.Won() || g.Stats.ShotsFired > 40 {
+		t.Errorf("Expected game won in at most %v shots, got: %v, shots: %v", 40, g.Won(), g.Stats.ShotsFired)
+	}
+	if _, err := g.AutoPlayStep(); err == nil {
+		t.Error("Expected error after the game is over")
+	}
+}
+
+func TestLegalPlacements_emptyBoard(t *testing.T) {
+	g := newTestGame()
+
+	for _, visible := range []bool{true, false} {
+		if got := len(g.LegalPlacements(4, visible)); got != 2*Rows*(Cols-3) {
+			t.Errorf("Expected number of placements: %v, got: %v for visible: %v", 2*Rows*(Cols-3), got, visible)
+		}
+	}
+	if got := len(g.LegalPlacements(1, true)); got != Rows*Cols {
+		t.Errorf("Expected number of single slot placements: %v, got: %v", Rows*Cols, got)
+	}
+}
+
+func TestLegalPlacements_visibleAndTrueBoard(t *testing.T) {
+	g := newTestGame(testShip{5, Position{0, 0}, Horizontal})
+	g.Shot(Position{5, 5})
+
+	visible, actual := len(g.LegalPlacements(4, true)), len(g.LegalPlacements(4, false))
+	if visible != 140-8 {
+		t.Errorf("Expected number of visible placements: %v, got: %v", 140-8, visible)
+	}
+	if actual >= visible {
+		t.Errorf("Expected the hidden ship to block placements on the true board, got: %v, visible: %v", actual, visible)
+	}
+}
+
+func TestUncertaintyBySize_countsDropAfterMisses(t *testing.T) {
+	g := newTestGame(
+		testShip{4, Position{0, 0}, Horizontal},
+		testShip{2, Position{9, 8}, Horizontal},
+		testShip{2, Position{5, 0}, Vertical},
+	)
+	before := g.UncertaintyBySize()
+	if len(before) != 2 || before[4] != 140 || before[2] != 180 {
+		t.Errorf("Expected uncertainty: %v, got: %v", map[uint8]int{4: 140, 2: 180}, before)
+	}
+
+	for _, p := range []Position{{2, 2}, {4, 4}, {7, 7}} {
+		g.Shot(p)
+	}
+	after := g.UncertaintyBySize()
+	for size, count := range before {
+		if after[size] >= count {
+			t.Errorf("Expected uncertainty of size %v to drop below %v, got: %v", size, count, after[size])
+		}
+	}
+}
@@ -0,0 +1,241 @@
+package battleships
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	colorReset = "\x1b[0m"
+	colorRed   = "\x1b[31m"
+	colorBlue  = "\x1b[34m"
+	colorGray  = "\x1b[90m"
+)
+
+// String returns a text representation of the board with rows marked by letters and columns marked by numbers
+func (b *Board) String() string {
+	return b.Render(func(p Position, v byte) string {
+		return fmt.Sprintf("%3c", v)
+	})
+}
+
+// StringColored works the same way as String, but surrounds slots with ANSI color codes.
+// Hit and sunk ships are red, missed shots are blue and undamaged ships are gray
+func (b *Board) StringColored() string {
+	return b.Render(func(p Position, v byte) string {
+		color := slotColor(v)
+		if color == "" {
+			return fmt.Sprintf("%3c", v)
+		}
+		return fmt.Sprintf("  %v%c%v", color, v, colorReset)
+	})
+}
+
+// CodeMatrix returns the board as a matrix of numeric codes, which is convenient for graphics libraries.
+// Water is coded as 0, an undamaged ship as 1, a hit or sunk ship as 2 and a missed shot as 3. Unrecognized slots are coded as -1
+func (b *Board) CodeMatrix() [][]int {
+	codes := map[CellState]int{WaterState: 0, ShipState: 1, HitState: 2, MissState: 3, SunkState: 2}
+
+	matrix := make([][]int, Rows)
+	for i := range matrix {
+		matrix[i] = make([]int, Cols)
+		for j := range matrix[i] {
+			code, ok := codes[b.StateAt(Position{row: uint8(i), col: uint8(j)})]
+			if !ok {
+				code = -1
+			}
+			matrix[i][j] = code
+		}
+	}
+	return matrix
+}
+
+func slotColor(v byte) string {
+	switch v {
+	case HitShipSlot, SunkShipSlot:
+		return colorRed
+	case MissedSlot:
+		return colorBlue
+	case ShipSlot:
+		return colorGray
+	}
+	return ""
+}
+
+// WriteCSV writes the board to w as comma-separated codes returned by CodeMatrix.
+// The first row contains numbers of columns and the first column contains letters of rows
+func (b *Board) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	header := []string{""}
+	for j := 0; j < Cols; j++ {
+		header = append(header, strconv.Itoa(j+1))
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for i, row := range b.CodeMatrix() {
+		record := []string{string('A' + rune(i))}
+		for _, code := range row {
+			record = append(record, strconv.Itoa(code))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// RenderChangedRows returns rows, that differ from the previous snapshot of the board, keyed by index of a row.
+// Rows are rendered the same way as by String, without a trailing new line, so a terminal can redraw only them
+func (b *Board) RenderChangedRows(prev *Board) map[int]string {
+	changed := make(map[int]string)
+	for i := 0; i < Rows; i++ {
+		if b[i] != prev[i] {
+			changed[i] = b.renderRow(i, 0, Cols, func(p Position, v byte) string {
+				return fmt.Sprintf("%3c", v)
+			})
+		}
+	}
+	return changed
+}
+
+// StringRegion works the same way as String, but renders only a window of the board.
+// The window starts at the given top row and left column and is cut to fit within the board
+func (b *Board) StringRegion(top, left, height, width int) string {
+	return b.renderRegion(top, left, height, width, func(p Position, v byte) string {
+		return fmt.Sprintf("%3c", v)
+	})
+}
+
+// StringTransposed works the same way as String, but displays the board transposed: numbers of columns go down
+// and letters of rows go across, matching input accepted by ConvertTransposedInputToPosition
+func (b *Board) StringTransposed() string {
+	buf := bytes.Buffer{}
+	buf.WriteString("  ")
+	for i := 0; i < Rows; i++ {
+		buf.WriteString(fmt.Sprintf("%3c", 'A'+i))
+	}
+	buf.WriteString("\n")
+
+	for j := 0; j < Cols; j++ {
+		buf.WriteString(fmt.Sprintf("%2d", j+1))
+		for i := 0; i < Rows; i++ {
+			buf.WriteString(fmt.Sprintf("%3c", b[i][j]))
+		}
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+// RenderOptions describes layout of the board's text representation created by RenderWith
+type RenderOptions struct {
+	// CellWidth is number of characters used by every slot. Zero means 3, the width used by String
+	CellWidth int
+	// LeftPad is number of spaces preceding every line
+	LeftPad int
+	// ShowLabels adds numbers of columns above the board and letters of rows on its left
+	ShowLabels bool
+}
+
+// RenderWith creates a text representation of the board laid out as described by the options
+func (b *Board) RenderWith(opts RenderOptions) string {
+	width := opts.CellWidth
+	if width <= 0 {
+		width = 3
+	}
+	pad := strings.Repeat(" ", maxInt(0, opts.LeftPad))
+
+	buf := bytes.Buffer{}
+	if opts.ShowLabels {
+		buf.WriteString(pad + "  ")
+		for j := 0; j < Cols; j++ {
+			buf.WriteString(fmt.Sprintf("%*d", width, j+1))
+		}
+		buf.WriteString("\n")
+	}
+	for i := 0; i < Rows; i++ {
+		buf.WriteString(pad)
+		if opts.ShowLabels {
+			buf.WriteString(fmt.Sprintf("%2c", 'A'+i))
+		}
+		for j := 0; j < Cols; j++ {
+			buf.WriteString(fmt.Sprintf("%*c", width, b[i][j]))
+		}
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+// GoString returns the board as a Go composite literal, which can be pasted into a test as a fixture
+func (b *Board) GoString() string {
+	buf := bytes.Buffer{}
+	buf.WriteString("Board{\n")
+	for i := range b {
+		buf.WriteString("\t{")
+		for j, v := range b[i] {
+			if j > 0 {
+				buf.WriteString(", ")
+			}
+			buf.WriteString(strconv.QuoteRune(rune(v)))
+		}
+		buf.WriteString("},\n")
+	}
+	buf.WriteString("}")
+	return buf.String()
+}
+
+// Render creates a text representation of the board with labels of rows and columns, using cell function
+// to format each of the slots. It allows frontends to attach custom information to the slots, e.g. ordinals of shots
+func (b *Board) Render(cell func(p Position, v byte) string) string {
+	return b.renderRegion(0, 0, Rows, Cols, cell)
+}
+
+// renderRegion creates a text representation of a window of the board using cell function to format each of the slots
+func (b *Board) renderRegion(top, left, height, width int, cell func(p Position, v byte) string) string {
+	bottom := minInt(Rows, top+height)
+	right := minInt(Cols, left+width)
+	top = maxInt(0, top)
+	left = maxInt(0, left)
+
+	buf := bytes.Buffer{}
+	buf.WriteString("  ")
+	for j := left; j < right; j++ {
+		buf.WriteString(fmt.Sprintf("%3d", j+1))
+	}
+	buf.WriteString("\n")
+
+	for i := top; i < bottom; i++ {
+		buf.WriteString(b.renderRow(i, left, right, cell))
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+// renderRow creates a text representation of slots of a single row from left to right column, preceded by a row label
+func (b *Board) renderRow(i, left, right int, cell func(p Position, v byte) string) string {
+	buf := bytes.Buffer{}
+	buf.WriteString(fmt.Sprintf("%2c", 'A'+i))
+	for j := left; j < right; j++ {
+		buf.WriteString(cell(Position{row: uint8(i), col: uint8(j)}, b[i][j]))
+	}
+	return buf.String()
+}
+
+func minInt(x, y int) int {
+	if x < y {
+		return x
+	}
+	return y
+}
+
+func maxInt(x, y int) int {
+	if x > y {
+		return x
+	}
+	return y
+}
@@ -0,0 +1,255 @@
+package battleships
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestString_boardRendered(t *testing.T) {
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal})
+	g.Shot(Position{0, 0})
+	g.Shot(Position{1, 9})
+
+	lines := strings.Split(g.Board(false).String(), "\n")
+
+	expected := []string{
+		"    1  2  3  4  5  6  7  8  9 10",
+		" A  X  S  -  -  -  -  -  -  -  -",
+		" B  -  -  -  -  -  -  -  -  -  O",
+	}
+	for i, e := range expected {
+		if lines[i] != e {
+			t.Errorf("Expected line: %q, got: %q", e, lines[i])
+		}
+	}
+	if len(lines) != Rows+2 {
+		t.Errorf("Expected number of lines: %v, got: %v", Rows+2, len(lines))
+	}
+}
+
+func TestStringColored_escapeSequences(t *testing.T) {
+	b := Board{}
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			b[i][j] = EmptySlot
+		}
+	}
+	b[0][0] = HitShipSlot
+	b[0][1] = MissedSlot
+	b[0][2] = ShipSlot
+
+	out := b.StringColored()
+
+	data := []string{
+		colorRed + string(HitShipSlot) + colorReset,
+		colorBlue + string(MissedSlot) + colorReset,
+		colorGray + string(ShipSlot) + colorReset,
+	}
+	for _, d := range data {
+		if !strings.Contains(out, d) {
+			t.Errorf("Colored output doesn't contain %q", d)
+		}
+	}
+	if strings.Contains(b.String(), "\x1b") {
+		t.Error("Plain output contains escape sequences")
+	}
+}
+
+func TestStringRegion_windowRendered(t *testing.T) {
+	g := newTestGame(testShip{3, Position{4, 4}, Horizontal})
+	g.Shot(Position{4, 5})
+	g.Shot(Position{5, 6})
+
+	data := []struct {
+		top, left, height, width int
+		expected                 string
+	}{
+		{3, 4, 3, 3, "    5  6  7\n D  -  -  -\n E  S  X  S\n F  -  -  O\n"},
+		{8, 8, 3, 3, "    9 10\n I  -  -\n J  -  -\n"},
+		{-1, -1, 2, 2, "    1\n A  -\n"},
+	}
+
+	b := g.Board(false)
+	for _, d := range data {
+		got := b.StringRegion(d.top, d.left, d.height, d.width)
+		if got != d.expected {
+			t.Errorf("Expected region:\n%v\ngot:\n%v", d.expected, got)
+		}
+	}
+}
+
+func TestCodeMatrix_slotsMapped(t *testing.T) {
+	data := []struct {
+		val  byte
+		code int
+	}{
+		{EmptySlot, 0},
+		{ShipSlot, 1},
+		{HitShipSlot, 2},
+		{MissedSlot, 3},
+		{'?', -1},
+	}
+
+	b := Board{}
+	for j, d := range data {
+		b[1][j] = d.val
+	}
+	matrix := b.CodeMatrix()
+
+	if len(matrix) != Rows || len(matrix[0]) != Cols {
+		t.Fatalf("Expected matrix %vx%v, got: %vx%v", Rows, Cols, len(matrix), len(matrix[0]))
+	}
+	for j, d := range data {
+		if matrix[1][j] != d.code {
+			t.Errorf("Expected code: %v, got: %v for slot: %q", d.code, matrix[1][j], d.val)
+		}
+	}
+}
+
+func TestStringTransposed_boardRendered(t *testing.T) {
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal})
+	g.Shot(Position{0, 0})
+	g.Shot(Position{1, 9})
+
+	lines := strings.Split(g.Board(false).StringTransposed(), "\n")
+
+	expected := []string{
+		"    A  B  C  D  E  F  G  H  I  J",
+		" 1  X  -  -  -  -  -  -  -  -  -",
+		" 2  S  -  -  -  -  -  -  -  -  -",
+	}
+	for i, e := range expected {
+		if lines[i] != e {
+			t.Errorf("Expected line: %q, got: %q", e, lines[i])
+		}
+	}
+	if lines[Cols] != "10  -  O  -  -  -  -  -  -  -  -" {
+		t.Errorf("Expected last line to contain the missed shot, got: %q", lines[Cols])
+	}
+}
+
+func TestGoString_parsesBackToBoard(t *testing.T) {
+	g := newTestGame(testShip{3, Position{0, 0}, Horizontal})
+	g.Shot(Position{0, 1})
+	g.Shot(Position{9, 9})
+	b := g.Board(false)
+
+	expr, err := parser.ParseExpr(fmt.Sprintf("%#v", b))
+	if err != nil {
+		t.Fatalf("Go representation couldn't be parsed: %v", err)
+	}
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok || len(lit.Elts) != Rows {
+		t.Fatalf("Expected composite literal with %v rows, got: %#v", Rows, expr)
+	}
+	var parsed Board
+	for i, row := range lit.Elts {
+		for j, v := range row.(*ast.CompositeLit).Elts {
+			slot, err := strconv.Unquote(v.(*ast.BasicLit).Value)
+			if err != nil {
+				t.Fatalf("Slot %v couldn't be unquoted: %v", v.(*ast.BasicLit).Value, err)
+			}
+			parsed[i][j] = slot[0]
+		}
+	}
+	if parsed != *b {
+		t.Errorf("Parsed board differs from the original one:\n%v", parsed.Differences(b))
+	}
+}
+
+func TestRender_shotOrdinals(t *testing.T) {
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal})
+	g.Shot(Position{0, 1})
+	g.Shot(Position{1, 0})
+
+	ordinals := make(map[Position]int)
+	for _, r := range g.History() {
+		ordinals[r.Position] = r.Ordinal
+	}
+	lines := strings.Split(g.Board(true).Render(func(p Position, v byte) string {
+		if n, ok := ordinals[p]; ok {
+			return fmt.Sprintf("%3d", n)
+		}
+		return fmt.Sprintf("%3c", v)
+	}), "\n")
+
+	expected := []string{
+		"    1  2  3  4  5  6  7  8  9 10",
+		" A  -  1  -  -  -  -  -  -  -  -",
+		" B  2  -  -  -  -  -  -  -  -  -",
+	}
+	for i, e := range expected {
+		if lines[i] != e {
+			t.Errorf("Expected line: %q, got: %q", e, lines[i])
+		}
+	}
+}
+
+func TestWriteCSV_codesWritten(t *testing.T) {
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal})
+	g.Shot(Position{0, 0})
+	g.Shot(Position{1, 9})
+
+	buf := bytes.Buffer{}
+	if err := g.Board(false).WriteCSV(&buf); err != nil {
+		t.Fatalf("Error has been returned %v", err)
+	}
+
+	expected := ",1,2,3,4,5,6,7,8,9,10\n" +
+		"A,2,1,0,0,0,0,0,0,0,0\n" +
+		"B,0,0,0,0,0,0,0,0,0,3\n"
+	for i := 2; i < Rows; i++ {
+		expected += string('A'+rune(i)) + ",0,0,0,0,0,0,0,0,0,0\n"
+	}
+	if buf.String() != expected {
+		t.Errorf("Expected CSV:\n%v\ngot:\n%v", expected, buf.String())
+	}
+}
+
+func TestRenderChangedRows_singleShot(t *testing.T) {
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal})
+	prev := g.Board(false)
+	g.Shot(Position{3, 4})
+
+	changed := g.Board(false).RenderChangedRows(prev)
+	expected := " D  -  -  -  -  O  -  -  -  -  -"
+	if len(changed) != 1 || changed[3] != expected {
+		t.Errorf("Expected a single changed row %v: %q, got: %v", 3, expected, changed)
+	}
+	if changed := prev.RenderChangedRows(prev); len(changed) != 0 {
+		t.Errorf("Expected no changed rows, got: %v", changed)
+	}
+}
+
+func TestRenderWith_customLayout(t *testing.T) {
+	g := newTestGame(testShip{2, Position{0, 0}, Horizontal})
+	g.Shot(Position{0, 0})
+	b := g.Board(false)
+
+	data := []struct {
+		opts     RenderOptions
+		expected []string
+	}{
+		{RenderOptions{CellWidth: 2, LeftPad: 1, ShowLabels: true}, []string{
+			"    1 2 3 4 5 6 7 8 910",
+			"  A X S - - - - - - - -",
+		}},
+		{RenderOptions{CellWidth: 1}, []string{"XS--------", "----------"}},
+	}
+	for _, d := range data {
+		lines := strings.Split(b.RenderWith(d.opts), "\n")
+		for i, e := range d.expected {
+			if lines[i] != e {
+				t.Errorf("Expected line: %q, got: %q for options: %+v", e, lines[i], d.opts)
+			}
+		}
+	}
+	if got := b.RenderWith(RenderOptions{ShowLabels: true}); got != b.String() {
+		t.Errorf("Expected default options to match String, got:\n%v", got)
+	}
+}
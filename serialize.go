@@ -0,0 +1,455 @@
+package battleships
+
+import (
+	"bufio"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// SaveVersion defines version of the format written by Save and MarshalJSON
+const SaveVersion = 3
+
+type savedGame struct {
+	Version     int         `json:"version"`
+	Options     Options     `json:"options"`
+	Board       Board       `json:"board"`
+	Ships       []savedShip `json:"ships"`
+	History     []savedShot `json:"history"`
+	Stats       Statistics  `json:"stats"`
+	Initialized bool        `json:"initialized"`
+	Forfeited   bool        `json:"forfeited,omitempty"`
+	StartedAt   time.Time   `json:"startedAt"`
+	FinishedAt  time.Time   `json:"finishedAt"`
+	// Paused is total duration of pauses, including the current one, which is finished by saving the game
+	Paused time.Duration `json:"paused,omitempty"`
+	// TurnTimeout is restored without the start of the current turn, so the limit applies again from the next turn
+	TurnTimeout time.Duration `json:"turnTimeout,omitempty"`
+	// Armor lists slots, that still withstand some hits before being damaged
+	Armor []savedArmor `json:"armor,omitempty"`
+}
+
+type savedShip struct {
+	Size      uint8      `json:"size"`
+	Health    uint8      `json:"health"`
+	Direction Direction  `json:"direction"`
+	Armor     uint8      `json:"armor,omitempty"`
+	Shape     []Position `json:"shape,omitempty"`
+	Cells     []Position `json:"cells"`
+}
+
+type savedShot struct {
+	Position Position    `json:"position"`
+	Slot     string      `json:"slot"`
+	Ships    []int       `json:"ships"`
+	Stats    Statistics  `json:"stats"`
+	Changes  []savedSlot `json:"changes,omitempty"`
+	Armored  bool        `json:"armored,omitempty"`
+}
+
+type savedArmor struct {
+	Position Position `json:"position"`
+	Hits     uint8    `json:"hits"`
+}
+
+type savedSlot struct {
+	Position Position `json:"position"`
+	Slot     string   `json:"slot"`
+}
+
+// LoadBoardFile reads a board drawn in a text file. The file needs to contain a line for every row of the board,
+// each of them consisting of slot runes, e.g. "--SSS-----". Empty lines are ignored
+func LoadBoardFile(path string) (*Board, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return readBoard(f)
+}
+
+func readBoard(r io.Reader) (*Board, error) {
+	b := &Board{}
+	rows := 0
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if rows == Rows {
+			return nil, fmt.Errorf("Expected %v rows of the board, got more", Rows)
+		}
+		if len(line) != Cols {
+			return nil, fmt.Errorf("Expected %v slots in row %v, got %v", Cols, rows+1, len(line))
+		}
+		for j := 0; j < Cols; j++ {
+			if slotState(line[j]) == UnknownState {
+				return nil, fmt.Errorf("Unknown slot %q in row %v", line[j], rows+1)
+			}
+		}
+		copy(b[rows][:], line)
+		rows++
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if rows != Rows {
+		return nil, fmt.Errorf("Expected %v rows of the board, got %v", Rows, rows)
+	}
+	return b, nil
+}
+
+// Save writes the whole state of the game to w, so it can be restored later with Load
+func (g *Game) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(g)
+}
+
+// Load reads a game written by Save. Returns error, if the data is malformed or written in an unknown version
+func Load(r io.Reader) (*Game, error) {
+	g := &Game{}
+	if err := json.NewDecoder(r).Decode(g); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+// SaveSession writes all of the games to w as a JSON array, so they can be restored later with LoadSession
+func SaveSession(w io.Writer, games []*Game) error {
+	if games == nil {
+		games = []*Game{}
+	}
+	return json.NewEncoder(w).Encode(games)
+}
+
+// LoadSession reads games written by SaveSession. Returns error, if any of the games is malformed
+func LoadSession(r io.Reader) ([]*Game, error) {
+	var games []*Game
+	if err := json.NewDecoder(r).Decode(&games); err != nil {
+		return nil, err
+	}
+	for i, g := range games {
+		if g == nil {
+			return nil, fmt.Errorf("Game %v of the session is empty", i+1)
+		}
+	}
+	return games, nil
+}
+
+// shapedCode marks a shaped ship in the share code, in place of its direction
+const shapedCode = 2
+
+var shareEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// ShareCode returns a short code describing the layout of ships, so the same puzzle can be set up by FromShareCode.
+// Every ship is encoded in a few bytes with its position, direction, size, armor and shape,
+// followed by a CRC-32 checksum. Shots and options of the game aren't included
+func (g *Game) ShareCode() string {
+	var data []byte
+	for _, p := range g.ExportLayout() {
+		direction := byte(p.Direction)
+		if len(p.Ship.shape) > 0 {
+			direction = shapedCode
+		}
+		data = append(data, direction, cellCode(p.Position), p.Ship.size, p.Ship.armor)
+		for _, o := range p.Ship.shape {
+			data = append(data, cellCode(o))
+		}
+	}
+	data = binary.BigEndian.AppendUint32(data, crc32.ChecksumIEEE(data))
+	return shareEncoding.EncodeToString(data)
+}
+
+// FromShareCode returns a new game with ships placed as described by a code returned by ShareCode.
+// Returns error, if the code is corrupt or the ships can't be placed
+func FromShareCode(code string) (*Game, error) {
+	data, err := shareEncoding.DecodeString(code)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid share code: %v", err)
+	}
+	if len(data) < 4 {
+		return nil, errors.New("Invalid share code: too short")
+	}
+	data, sum := data[:len(data)-4], binary.BigEndian.Uint32(data[len(data)-4:])
+	if crc32.ChecksumIEEE(data) != sum {
+		return nil, errors.New("Invalid share code: checksum mismatch")
+	}
+
+	var layout []Placement
+	for len(data) > 0 {
+		if len(data) < 4 {
+			return nil, errors.New("Invalid share code: truncated ship")
+		}
+		direction, pos, size, armor := data[0], data[1], data[2], data[3]
+		data = data[4:]
+		if pos >= Rows*Cols {
+			return nil, fmt.Errorf("Invalid share code: unknown slot %v", pos)
+		}
+		p := Placement{Ship: NewArmoredShip(size, armor), Position: cellPosition(pos), Direction: Direction(direction)}
+		if direction == shapedCode {
+			if len(data) < int(size) {
+				return nil, errors.New("Invalid share code: truncated shape")
+			}
+			offsets := make([]Position, size)
+			for i := range offsets {
+				if data[i] >= Rows*Cols {
+					return nil, fmt.Errorf("Invalid share code: unknown offset %v", data[i])
+				}
+				offsets[i] = cellPosition(data[i])
+			}
+			data = data[size:]
+			p.Ship = NewShapedShip(offsets...).ship()
+			p.Ship.armor = armor
+			p.Direction = NoDirection
+		}
+		layout = append(layout, p)
+	}
+
+	g := &Game{}
+	if err := g.ImportLayout(layout); err != nil {
+		return nil, err
+	}
+	return g, nil
+}
+
+func cellCode(p Position) byte {
+	return p.row*Cols + p.col
+}
+
+func cellPosition(code byte) Position {
+	return Position{row: code / Cols, col: code % Cols}
+}
+
+// MarshalJSON encodes the whole state of the game together with the version of the format
+func (g *Game) MarshalJSON() ([]byte, error) {
+	s := savedGame{
+		Version:     SaveVersion,
+		Options:     g.opts,
+		Board:       g.board,
+		Stats:       g.Stats,
+		Initialized: g.initialized,
+		Forfeited:   g.forfeited,
+		StartedAt:   g.startedAt,
+		FinishedAt:  g.finishedAt,
+		TurnTimeout: g.turnTimeout,
+		Paused:      g.pausedFor(),
+		Ships:       make([]savedShip, len(g.ships)),
+		History:     make([]savedShot, len(g.history)),
+	}
+	for i, ship := range g.ships {
+		s.Ships[i] = savedShip{Size: ship.size, Health: ship.health, Direction: ship.direction, Armor: ship.armor, Shape: ship.shape, Cells: ship.cells}
+	}
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			if g.armor[i][j] > 0 {
+				s.Armor = append(s.Armor, savedArmor{Position: Position{row: uint8(i), col: uint8(j)}, Hits: g.armor[i][j]})
+			}
+		}
+	}
+	for i, r := range g.history {
+		s.History[i] = savedShot{Position: r.pos, Slot: string(r.slot), Ships: r.ships, Stats: r.stats, Armored: r.armored}
+		for _, c := range r.changes {
+			s.History[i].Changes = append(s.History[i].Changes, savedSlot{Position: c.pos, Slot: string(c.slot)})
+		}
+	}
+	return json.Marshal(&s)
+}
+
+// UnmarshalJSON decodes a game encoded by MarshalJSON. Data written in older versions of the format is migrated,
+// data in unknown versions is rejected
+func (g *Game) UnmarshalJSON(data []byte) error {
+	var v struct {
+		Version int `json:"version"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	var s savedGame
+	switch v.Version {
+	case 1:
+		if err := migrateV1(data, &s); err != nil {
+			return err
+		}
+	case 2:
+		if err := migrateV2(data, &s); err != nil {
+			return err
+		}
+	case 3:
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("Unsupported save version %v, supported versions are 1 to %v", v.Version, SaveVersion)
+	}
+	return g.restore(s)
+}
+
+// migrateV1 decodes data in version 1 of the format, in which every shot referred to a single damaged ship
+func migrateV1(data []byte, s *savedGame) error {
+	var v1 struct {
+		History []struct {
+			Ship int `json:"ship"`
+		} `json:"history"`
+	}
+	if err := migrateV2(data, s); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, &v1); err != nil {
+		return err
+	}
+	for i, r := range v1.History {
+		if r.Ship >= 0 {
+			s.History[i].Ships = []int{r.Ship}
+		}
+	}
+	return nil
+}
+
+// migrateV2 decodes data in version 2 of the format, which had neither armored nor shaped ships
+func migrateV2(data []byte, s *savedGame) error {
+	if err := json.Unmarshal(data, s); err != nil {
+		return err
+	}
+	s.Armor = nil
+	for i := range s.Ships {
+		s.Ships[i].Armor = 0
+		s.Ships[i].Shape = nil
+	}
+	for i := range s.History {
+		s.History[i].Armored = false
+	}
+	return nil
+}
+
+// IsConsistent checks, that the board, ships and statistics of the game, e.g. a loaded one, describe a possible state.
+// Returns false together with description of the first inconsistency found
+func (g *Game) IsConsistent() (bool, string) {
+	var owned [Rows][Cols]bool
+	sunk := 0
+	for idx, s := range g.ships {
+		afloat := 0
+		for _, c := range s.cells {
+			if !isWithinBoard(c.row, c.col) {
+				return false, fmt.Sprintf("Cell %v of ship %v is outside of the board", c, idx)
+			}
+			if !isShipSlot(g.board.At(c)) {
+				return false, fmt.Sprintf("Cell %v of ship %v is not marked as a ship on the board", c, idx)
+			}
+			if g.board.At(c) == ShipSlot {
+				afloat++
+			}
+			owned[c.row][c.col] = true
+		}
+		if s.health == 0 && afloat > 0 {
+			return false, fmt.Sprintf("Ship %v is sunk, but %v of its cells are not hit", idx, afloat)
+		}
+		if int(s.health) != afloat {
+			return false, fmt.Sprintf("Ship %v has health %v, but %v cells not hit", idx, s.health, afloat)
+		}
+		if s.health == 0 {
+			sunk++
+		}
+	}
+
+	hits := 0
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			if isShipSlot(g.board[i][j]) && !owned[i][j] {
+				return false, fmt.Sprintf("Slot %v is marked as a ship, but doesn't belong to any ship", Position{row: uint8(i), col: uint8(j)})
+			}
+			if isShot(g.board[i][j]) && isShipSlot(g.board[i][j]) {
+				hits++
+			}
+		}
+	}
+	if g.Stats.SunkShips != sunk {
+		return false, fmt.Sprintf("Statistics count %v sunk ships, but %v ships are sunk", g.Stats.SunkShips, sunk)
+	}
+	if g.Stats.Hits > g.Stats.ShotsFired {
+		return false, fmt.Sprintf("Statistics count %v hits, but only %v shots", g.Stats.Hits, g.Stats.ShotsFired)
+	}
+	if g.Stats.Hits < hits {
+		return false, fmt.Sprintf("Statistics count %v hits, but %v ship slots are hit", g.Stats.Hits, hits)
+	}
+	return true, ""
+}
+
+// restore replaces state of the game with the saved one, after checking it refers only to existing ships and slots
+func (g *Game) restore(s savedGame) error {
+	loaded := Game{
+		opts:        s.Options,
+		board:       s.Board,
+		Stats:       s.Stats,
+		initialized: s.Initialized,
+		forfeited:   s.Forfeited,
+		startedAt:   s.StartedAt,
+		finishedAt:  s.FinishedAt,
+		turnTimeout: s.TurnTimeout,
+		paused:      s.Paused,
+	}
+	for i := 0; i < Rows; i++ {
+		for j := 0; j < Cols; j++ {
+			loaded.shipIndex[i][j] = -1
+		}
+	}
+
+	for idx, ship := range s.Ships {
+		if int(ship.Size) != len(ship.Cells) {
+			return fmt.Errorf("Ship %v has size %v, but %v cells", idx, ship.Size, len(ship.Cells))
+		}
+		for _, c := range ship.Cells {
+			if loaded.shipIndex[c.row][c.col] >= 0 && !s.Options.AllowOverlap {
+				return fmt.Errorf("Cell %v belongs to more than one ship", c)
+			}
+			if v := loaded.board.At(c); v != ShipSlot && v != HitShipSlot && v != SunkShipSlot {
+				return fmt.Errorf("Cell %v of ship %v is not marked as a ship on the board", c, idx)
+			}
+			loaded.shipIndex[c.row][c.col] = idx
+		}
+		loaded.ships = append(loaded.ships, Ship{
+			size:      ship.Size,
+			health:    ship.Health,
+			direction: ship.Direction,
+			armor:     ship.Armor,
+			shape:     ship.Shape,
+			cells:     ship.Cells,
+		})
+	}
+	for _, a := range s.Armor {
+		if !isWithinBoard(a.Position.row, a.Position.col) || loaded.shipIndex[a.Position.row][a.Position.col] < 0 {
+			return fmt.Errorf("Armored cell %v doesn't belong to any ship", a.Position)
+		}
+		loaded.armor[a.Position.row][a.Position.col] = a.Hits
+	}
+
+	for _, r := range s.History {
+		if len(r.Slot) != 1 {
+			return fmt.Errorf("Invalid slot %q in the history", r.Slot)
+		}
+		for _, idx := range r.Ships {
+			if idx < 0 || idx >= len(loaded.ships) {
+				return fmt.Errorf("Shot at %v refers to unknown ship %v", r.Position, idx)
+			}
+		}
+		record := shotRecord{pos: r.Position, slot: r.Slot[0], ships: r.Ships, stats: r.Stats, armored: r.Armored}
+		for _, c := range r.Changes {
+			if len(c.Slot) != 1 {
+				return fmt.Errorf("Invalid slot %q in the history", c.Slot)
+			}
+			record.changes = append(record.changes, slotChange{pos: c.Position, slot: c.Slot[0]})
+		}
+		loaded.history = append(loaded.history, record)
+	}
+
+	*g = loaded
+	return nil
+}
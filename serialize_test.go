@@ -0,0 +1,257 @@
+package battleships
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"strings"
+	"testing"
+)
+
+const savedV1 = `{
+	"version": 1,
+	"options": {"adjacency": 0, "allowRepeatShots": false},
+	"board": [
+		"XS--------",
+		"----------",
+		"---------O",
+		"----------",
+		"----------",
+		"----------",
+		"----------",
+		"----------",
+		"----------",
+		"----------"
+	],
+	"ships": [{"size": 2, "health": 1, "direction": 0, "cells": ["A1", "A2"]}],
+	"history": [
+		{"position": "A1", "slot": "S", "ship": 0, "stats": {"shotsFired": 0, "initialShips": 1, "sunkShips": 0}},
+		{"position": "C10", "slot": "-", "ship": -1, "stats": {"shotsFired": 1, "initialShips": 1, "sunkShips": 0}}
+	],
+	"stats": {"shotsFired": 2, "initialShips": 1, "sunkShips": 0},
+	"initialized": true
+}`
+
+func TestLoad_version1(t *testing.T) {
+	g, err := Load(strings.NewReader(savedV1))
+	if err != nil {
+		t.Fatalf("Error has been returned %v", err)
+	}
+
+	if g.Stats.ShotsFired != 2 || len(g.history) != 2 {
+		t.Errorf("Statistics or history not restored: %v, %v", g.Stats, g.history)
+	}
+	if len(g.history[0].ships) != 1 || len(g.history[1].ships) != 0 {
+		t.Errorf("Damaged ships not migrated: %v", g.history)
+	}
+	hit, sunk, err := g.Shot(Position{0, 1})
+	if !hit || !sunk || err != nil {
+		t.Errorf("Expected the ship to sink, got hit: %v, sunk: %v, err: %v", hit, sunk, err)
+	}
+	if g.Playable() {
+		t.Error("Game is still playable after sinking the last ship")
+	}
+	if err := g.UndoN(3); err != nil {
+		t.Errorf("Loaded history couldn't be undone: %v", err)
+	}
+	if g.board.At(Position{0, 0}) != ShipSlot || g.ships[0].health != 2 {
+		t.Errorf("Undo didn't restore the ship, board: %v", g.board)
+	}
+}
+
+func TestLoad_version2(t *testing.T) {
+	blob := strings.Replace(savedV1, `"version": 1`, `"version": 2`, 1)
+	blob = strings.Replace(blob, `"ship": 0`, `"ships": [0]`, 1)
+	blob = strings.Replace(blob, `"ship": -1`, `"ships": []`, 1)
+
+	g, err := Load(strings.NewReader(blob))
+	if err != nil {
+		t.Fatalf("Error has been returned %v", err)
+	}
+
+	if g.ships[0].armor != 0 || len(g.ships[0].shape) != 0 || g.armor != ([Rows][Cols]uint8{}) {
+		t.Errorf("Expected ships without armor and shape, got: %v, %v", g.ships[0], g.armor)
+	}
+	hit, sunk, err := g.Shot(Position{0, 1})
+	if !hit || !sunk || err != nil {
+		t.Errorf("Expected the ship to sink, got hit: %v, sunk: %v, err: %v", hit, sunk, err)
+	}
+}
+
+func TestLoad_unknownVersion(t *testing.T) {
+	blob := strings.Replace(savedV1, `"version": 1`, `"version": 99`, 1)
+
+	_, err := Load(strings.NewReader(blob))
+	if err == nil || !strings.Contains(err.Error(), "99") {
+		t.Errorf("Expected error mentioning the version, got: %v", err)
+	}
+}
+
+func TestLoad_invalidShipCells(t *testing.T) {
+	blob := strings.Replace(savedV1, `["A1", "A2"]`, `["A1", "A3"]`, 1)
+
+	if _, err := Load(strings.NewReader(blob)); err == nil {
+		t.Error("No error returned for ship cell not marked on the board")
+	}
+}
+
+func TestIsConsistent_impossibleLoadedState(t *testing.T) {
+	g := &Game{}
+	g.FillBoard(StandardFleet())
+	for _, p := range []Position{{0, 0}, {4, 4}, {9, 9}} {
+		g.Shot(p)
+	}
+	if ok, reason := g.IsConsistent(); !ok {
+		t.Errorf("Expected played game to be consistent, got: %v", reason)
+	}
+
+	data := []struct {
+		old, new, reason string
+	}{
+		{`"health": 1`, `"health": 0`, "sunk"},
+		{`"stats": {"shotsFired": 2,`, `"stats": {"shotsFired": 2, "hits": 3,`, "hits"},
+		{`"XS--`, `"XSS-`, "doesn't belong"},
+	}
+	for _, d := range data {
+		loaded, err := Load(strings.NewReader(strings.Replace(savedV1, d.old, d.new, 1)))
+		if err != nil {
+			t.Fatalf("Error has been returned %v", err)
+		}
+		if ok, reason := loaded.IsConsistent(); ok || !strings.Contains(reason, d.reason) {
+			t.Errorf("Expected inconsistency mentioning %q, got: %v, %q", d.reason, ok, reason)
+		}
+	}
+}
+
+func TestSave_roundTrip(t *testing.T) {
+	g := &Game{}
+	g.FillBoard([]Ship{NewShip(5), NewShip(4), NewShip(4)})
+	g.Shot(Position{0, 0})
+	g.Shot(Position{5, 5})
+
+	buf := bytes.Buffer{}
+	if err := g.Save(&buf); err != nil {
+		t.Fatalf("Error has been returned %v", err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatalf("Error has been returned %v", err)
+	}
+
+	if loaded.board != g.board || loaded.shipIndex != g.shipIndex || loaded.Stats != g.Stats {
+		t.Error("Loaded game differs from the saved one")
+	}
+	if len(loaded.ships) != len(g.ships) || len(loaded.history) != len(g.history) {
+		t.Error("Ships or history of the loaded game differ from the saved one")
+	}
+}
+
+func TestSaveSession_roundTrip(t *testing.T) {
+	games := make([]*Game, 3)
+	for i := range games {
+		games[i] = &Game{}
+		games[i].FillBoard(StandardFleet())
+		for j := 0; j <= i; j++ {
+			games[i].Shot(Position{uint8(j), uint8(j)})
+		}
+	}
+
+	buf := bytes.Buffer{}
+	if err := SaveSession(&buf, games); err != nil {
+		t.Fatalf("Error has been returned %v", err)
+	}
+	loaded, err := LoadSession(&buf)
+	if err != nil {
+		t.Fatalf("Error has been returned %v", err)
+	}
+
+	if len(loaded) != len(games) {
+		t.Fatalf("Expected number of games: %v, got: %v", len(games), len(loaded))
+	}
+	for i, g := range games {
+		if loaded[i].board != g.board || loaded[i].Stats != g.Stats || len(loaded[i].history) != len(g.history) {
+			t.Errorf("Loaded game %v differs from the saved one", i)
+		}
+	}
+}
+
+func TestLoadSession_malformed(t *testing.T) {
+	inputs := []string{`{"version":2}`, `[null]`, `[{"version":99}]`}
+
+	for _, input := range inputs {
+		if _, err := LoadSession(strings.NewReader(input)); err == nil {
+			t.Errorf("Expected error for session: %v", input)
+		}
+	}
+}
+
+func TestSave_armorRoundTrip(t *testing.T) {
+	g := &Game{}
+	g.PlaceShip(NewArmoredShip(3, 3), Position{0, 0}, Horizontal)
+	g.Ready()
+	g.Shot(Position{0, 0})
+	g.Shot(Position{0, 1})
+
+	buf := bytes.Buffer{}
+	if err := g.Save(&buf); err != nil {
+		t.Fatalf("Error has been returned %v", err)
+	}
+	loaded, err := Load(&buf)
+	if err != nil {
+		t.Fatalf("Error has been returned %v", err)
+	}
+	if loaded.armor != g.armor || loaded.ships[0].armor != 3 {
+		t.Errorf("Expected armor: %v, got: %v", g.armor[0], loaded.armor[0])
+	}
+	loaded.Undo()
+	if loaded.armor[0][1] != 2 {
+		t.Errorf("Expected armor restored by undo of loaded game: %v, got: %v", 2, loaded.armor[0][1])
+	}
+}
+
+func TestShareCode_roundTrip(t *testing.T) {
+	g := &Game{}
+	ships := append(StandardFleet(), NewArmoredShip(2, 1))
+	if err := g.FillBoard(ships); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	g.Shot(Position{0, 0})
+
+	loaded, err := FromShareCode(g.ShareCode())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if loaded.shipIndex != g.shipIndex || layoutChecksum(loaded.ExportLayout(), "") != layoutChecksum(g.ExportLayout(), "") || loaded.Stats.ShotsFired != 0 {
+		t.Errorf("Expected new game with the same layout, got:\n%v", loaded.Board(false))
+	}
+}
+
+func TestFromShareCode_repeatedShapeOffsets(t *testing.T) {
+	// a shaped ship of size 3 at A1 with offsets A1, A1 and A2
+	data := []byte{shapedCode, 0, 3, 0, 0, 0, 1}
+	data = binary.BigEndian.AppendUint32(data, crc32.ChecksumIEEE(data))
+
+	if _, err := FromShareCode(shareEncoding.EncodeToString(data)); err == nil {
+		t.Error("Expected error for a shaped ship with repeated slots")
+	}
+	layout := []Placement{{Ship: NewShapedShip(Position{0, 0}, Position{0, 0}, Position{0, 1}).ship(), Direction: NoDirection}}
+	if err := (&Game{}).ImportLayout(layout); err == nil {
+		t.Error("Expected error importing a shaped ship with repeated slots")
+	}
+}
+
+func TestFromShareCode_corruptCode(t *testing.T) {
+	code := newTestGame(testShip{3, Position{0, 0}, Horizontal}).ShareCode()
+	corrupt := []byte(code)
+	if corrupt[0] == 'A' {
+		corrupt[0] = 'B'
+	} else {
+		corrupt[0] = 'A'
+	}
+
+	for _, c := range []string{string(corrupt), code[:len(code)-2], "not a code!", ""} {
+		if _, err := FromShareCode(c); err == nil {
+			t.Errorf("Expected error for corrupt code: %q", c)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package battleships
+
+// MergeStats returns statistics aggregated over several games, e.g. a series of games played by the same player.
+// Counters and scores are summed, the longest streak is the longest one of all games
+// and the current streak is taken from the last game
+func MergeStats(stats ...Statistics) Statistics {
+	var total Statistics
+	for _, s := range stats {
+		total.ShotsFired += s.ShotsFired
+		total.Hits += s.Hits
+		total.InitialShips += s.InitialShips
+		total.SunkShips += s.SunkShips
+		total.Score += s.Score
+		total.HintsUsed += s.HintsUsed
+		total.SkippedTurns += s.SkippedTurns
+		total.ScansUsed += s.ScansUsed
+		total.CurrentStreak = s.CurrentStreak
+		if s.LongestStreak > total.LongestStreak {
+			total.LongestStreak = s.LongestStreak
+		}
+	}
+	return total
+}
+
+// AddTo adds statistics of the game to the total aggregated by MergeStats
+func (g *Game) AddTo(total *Statistics) {
+	*total = MergeStats(*total, g.Stats)
+}
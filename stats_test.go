@@ -0,0 +1,33 @@
+package battleships
+
+import (
+	"testing"
+)
+
+func TestMergeStats(t *testing.T) {
+	first := Statistics{ShotsFired: 40, Hits: 17, InitialShips: 5, SunkShips: 5, Score: 34, CurrentStreak: 2, LongestStreak: 6, SkippedTurns: 2, ScansUsed: 1}
+	second := Statistics{ShotsFired: 30, Hits: 10, InitialShips: 5, SunkShips: 3, Score: 21, LongestStreak: 4, HintsUsed: 1, SkippedTurns: 1, ScansUsed: 2}
+
+	expected := Statistics{ShotsFired: 70, Hits: 27, InitialShips: 10, SunkShips: 8, Score: 55, LongestStreak: 6, HintsUsed: 1, SkippedTurns: 3, ScansUsed: 3}
+	if merged := MergeStats(first, second); merged != expected {
+		t.Errorf("Expected merged stats: %+v, got: %+v", expected, merged)
+	}
+	if merged := MergeStats(); merged != (Statistics{}) {
+		t.Errorf("Expected empty stats, got: %+v", merged)
+	}
+}
+
+func TestAddTo_accumulatesGames(t *testing.T) {
+	var total Statistics
+	for i := 0; i < 2; i++ {
+		g := newTestGame(testShip{2, Position{0, 0}, Horizontal})
+		g.Shot(Position{5, 5})
+		g.Shot(Position{0, 0})
+		g.AddTo(&total)
+	}
+
+	expected := Statistics{ShotsFired: 4, Hits: 2, InitialShips: 2, Score: 2 * HitScore, CurrentStreak: 1, LongestStreak: 1}
+	if total != expected {
+		t.Errorf("Expected total stats: %+v, got: %+v", expected, total)
+	}
+}